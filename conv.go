@@ -5,37 +5,47 @@
 package conv
 
 import (
-	"bytes"
 	"encoding"
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
-// 抛出一个类型无法转换的错误
-// val 当前值；t 目标类型。
-func typeError(val any, t string) error {
-	return fmt.Errorf("conv: %T:%v 无法转换成 %s 类型", val, val, t)
+// 字符串转 bool 值，供 Bool() 函数调用。
+// 除了 c.trueValues 和 c.falseValues 之外，数值字符串也可以被转换。
+func (c *Converter) str2Bool(str string) (bool, error) {
+	s := strings.TrimSpace(normalizeDigits(str))
+	for _, v := range c.trueValues {
+		if strings.EqualFold(s, v) {
+			return true, nil
+		}
+	}
+	for _, v := range c.falseValues {
+		if strings.EqualFold(s, v) {
+			return false, nil
+		}
+	}
+
+	if !c.strict {
+		if val, err := strconv.ParseFloat(s, 32); err == nil {
+			return val != 0, nil
+		}
+	}
+	return false, syntaxError(str, "bool", nil)
 }
 
-// 字符串转 bool 值，供 Bool() 函数调用。
-// 添加了一些 strconv.ParseFloat 不支持但又比较常用的字符串转换
-func str2Bool(str string) (bool, error) {
-	if val, err := strconv.ParseBool(str); err == nil {
-		return val, nil
-	} else if val, err := strconv.ParseFloat(str, 32); err == nil {
-		return val != 0, nil
-	}
-
-	switch strings.ToLower(strings.TrimSpace(str)) {
-	case "on":
-		return true, nil
-	case "off":
-		return false, nil
-	default:
-		return false, typeError(str, "bool")
+// 数值转换成 bool 值，val 为 f 的原始值，仅用于输出错误信息。
+func (c *Converter) num2Bool(val any, f float64) (bool, error) {
+	if c.strict && f != 0 && f != 1 {
+		return false, rangeError(val, "bool")
 	}
+	return f != 0, nil
 }
 
 // Bool 将 val 转换成 bool 类型或是在无法转换的情况下返回 error
@@ -43,35 +53,51 @@ func str2Bool(str string) (bool, error) {
 // 以下值被可以被正确转换：
 //
 //	123(true), 0(false),"-123"(true), "on"(true), "off"(false), "true"(true), "false"(false)
-func Bool(val any) (bool, error) {
+//
+// 字符串中的全角字符和 Unicode 数字会被转换成对应的 ASCII 字符，比如 "ｔｒｕｅ" 和 "１"。
+func Bool(val any) (bool, error) { return defaultConverter.Bool(val) }
+
+// Bool 将 val 转换成 bool 类型或是在无法转换的情况下返回 error
+func (c *Converter) Bool(val any) (bool, error) {
 	switch ret := val.(type) {
 	case bool:
 		return ret, nil
 	case int:
-		return ret != 0, nil
+		return c.num2Bool(ret, float64(ret))
 	case int8:
-		return ret != 0, nil
+		return c.num2Bool(ret, float64(ret))
 	case int32:
-		return ret != 0, nil
+		return c.num2Bool(ret, float64(ret))
 	case int64:
-		return ret != 0, nil
+		return c.num2Bool(ret, float64(ret))
 	case float32:
-		return ret != 0, nil
+		return c.num2Bool(ret, float64(ret))
 	case float64:
-		return ret != 0, nil
+		return c.num2Bool(ret, ret)
 	case uint:
-		return ret != 0, nil
+		return c.num2Bool(ret, float64(ret))
 	case uint8:
-		return ret != 0, nil
+		return c.num2Bool(ret, float64(ret))
 	case uint32:
-		return ret != 0, nil
+		return c.num2Bool(ret, float64(ret))
 	case uint64:
-		return ret != 0, nil
+		return c.num2Bool(ret, float64(ret))
 	case []byte:
-		return str2Bool(string(ret))
+		return c.str2Bool(string(ret))
 	case string:
-		return str2Bool(ret)
+		return c.str2Bool(ret)
 	default:
+		if v, ok := indirect(val); ok {
+			if v == nil {
+				return false, c.nilError(val, "bool")
+			}
+			return c.Bool(v)
+		}
+
+		if u, ok := underlying(val); ok {
+			ret, err := c.Bool(u)
+			return ret, replaceErrorValue(err, val)
+		}
 		return false, typeError(val, "bool")
 	}
 }
@@ -85,94 +111,150 @@ func MustBool(val any, def ...bool) bool {
 }
 
 // IntOf 转换成指定类型的符号整数
-func IntOf[T Signed](val any) (T, error) {
-	ret, err := toInt64(val)
+//
+// 字符串除了十进制整数之外，还支持 0x、0o 和 0b 前缀表示的十六进制、八进制和二进制，
+// 数字之间以下划线分隔的格式，比如 1_000，以及浮点数和科学计数法表示的数值，比如 1e6。
+// 可以通过 [WithDecimal] 禁用进制前缀和下划线，千位分隔符等格式则由 [WithNumberFormat] 指定。
+// 全角数字以及其它 Unicode 十进制数字，会被当作对应的 ASCII 数字，比如 －１２３。
+//
+// 如果转换后的值超出了 T 的取值范围，将返回 [ErrRange] 错误。
+func IntOf[T Signed](val any) (T, error) { return IntOfWith[T](defaultConverter, val) }
+
+// IntOfWith 采用 c 的转换规则实现 [IntOf]
+//
+// 由于方法不支持泛型，[Converter] 没有对应的 IntOf 方法，由此函数代替。
+func IntOfWith[T Signed](c *Converter, val any) (T, error) {
+	ret, err := c.toInt64(val)
 	if err != nil {
 		return 1, err
 	}
-	return T(ret), nil
+
+	if v := T(ret); int64(v) == ret {
+		return v, nil
+	}
+	return 1, rangeError(val, reflect.TypeOf(T(0)).String())
 }
 
 // UintOf 转换成指定类型的无符号整数
 //
-// 将一个有符号整数转换成无符号整数，负数将返回错误，正数和零正常转换
-func UintOf[T Unsigned](val any) (T, error) {
-	ret, err := toUint64(val)
+// 将一个有符号整数转换成无符号整数，负数将返回错误，正数和零正常转换。
+// 字符串支持的格式与 [IntOf] 相同。
+// 如果转换后的值超出了 T 的取值范围，将返回 [ErrRange] 错误。
+func UintOf[T Unsigned](val any) (T, error) { return UintOfWith[T](defaultConverter, val) }
+
+// UintOfWith 采用 c 的转换规则实现 [UintOf]
+//
+// 由于方法不支持泛型，[Converter] 没有对应的 UintOf 方法，由此函数代替。
+func UintOfWith[T Unsigned](c *Converter, val any) (T, error) {
+	ret, err := c.toUint64(val)
 	if err != nil {
 		return 0, err
 	}
-	return T(ret), nil
+
+	if v := T(ret); uint64(v) == ret {
+		return v, nil
+	}
+	return 0, rangeError(val, reflect.TypeOf(T(0)).String())
 }
 
 // Uint64 将 val 转换成 uint64 类型或是在无法转换的情况下返回 error
 func Uint64(val any) (uint64, error) { return UintOf[uint64](val) }
 
+// Uint64 将 val 转换成 uint64 类型或是在无法转换的情况下返回 error
+func (c *Converter) Uint64(val any) (uint64, error) { return UintOfWith[uint64](c, val) }
+
 // MustUint64 将 val 转换成 uint64 类型或是在无法转换的情况下返回 def 参数
 func MustUint64(val any, def ...uint64) uint64 { return MustUintOf(val, def...) }
 
 // Uint 将 val 转换成 uint 类型或是在无法转换的情况下返回 error
 func Uint(val any) (uint, error) { return UintOf[uint](val) }
 
+// Uint 将 val 转换成 uint 类型或是在无法转换的情况下返回 error
+func (c *Converter) Uint(val any) (uint, error) { return UintOfWith[uint](c, val) }
+
 // MustUint 将 val 转换成 uint 类型或是在无法转换的情况下返回 def 参数
 func MustUint(val any, def ...uint) uint { return MustUintOf(val, def...) }
 
 // Uint8 将 val 转换成 uint8 类型或是在无法转换的情况下返回 error
 func Uint8(val any) (uint8, error) { return UintOf[uint8](val) }
 
+// Uint8 将 val 转换成 uint8 类型或是在无法转换的情况下返回 error
+func (c *Converter) Uint8(val any) (uint8, error) { return UintOfWith[uint8](c, val) }
+
 // MustUint8 将 val 转换成 uint8 类型或是在无法转换的情况下返回 def 参数
 func MustUint8(val any, def ...uint8) uint8 { return MustUintOf(val, def...) }
 
 // Uint32 将 val 转换成 uint32 类型或是在无法转换的情况下返回 error
 func Uint32(val any) (uint32, error) { return UintOf[uint32](val) }
 
+// Uint32 将 val 转换成 uint32 类型或是在无法转换的情况下返回 error
+func (c *Converter) Uint32(val any) (uint32, error) { return UintOfWith[uint32](c, val) }
+
 // MustUint32 将 val 转换成 uint32 类型或是在无法转换的情况下返回 def 参数
 func MustUint32(val any, def ...uint32) uint32 { return MustUintOf(val, def...) }
 
 // Int64 将 val 转换成 int64 类型或是在无法转换的情况下返回 error
 func Int64(val any) (int64, error) { return IntOf[int64](val) }
 
+// Int64 将 val 转换成 int64 类型或是在无法转换的情况下返回 error
+func (c *Converter) Int64(val any) (int64, error) { return IntOfWith[int64](c, val) }
+
 // MustInt64 将 val 转换成 int64 类型或是在无法转换的情况下返回 def 参数
 func MustInt64(val any, def ...int64) int64 { return MustIntOf(val, def...) }
 
 // Int 将 val 转换成 int 类型或是在无法转换的情况下返回 error
 func Int(val any) (int, error) { return IntOf[int](val) }
 
+// Int 将 val 转换成 int 类型或是在无法转换的情况下返回 error
+func (c *Converter) Int(val any) (int, error) { return IntOfWith[int](c, val) }
+
 // MustInt 将 val 转换成 int 类型或是在无法转换的情况下返回 def 参数
 func MustInt(val any, def ...int) int { return MustIntOf(val, def...) }
 
 // Int8 将 val 转换成 int8 类型或是在无法转换的情况下返回 error
 func Int8(val any) (int8, error) { return IntOf[int8](val) }
 
+// Int8 将 val 转换成 int8 类型或是在无法转换的情况下返回 error
+func (c *Converter) Int8(val any) (int8, error) { return IntOfWith[int8](c, val) }
+
 // MustInt8 将 val 转换成 int8 类型或是在无法转换的情况下返回 def 参数
 func MustInt8(val any, def ...int8) int8 { return MustIntOf(val, def...) }
 
 // Int32 将 val 转换成 int32 类型或是在无法转换的情况下返回 error
 func Int32(val any) (int32, error) { return IntOf[int32](val) }
 
+// Int32 将 val 转换成 int32 类型或是在无法转换的情况下返回 error
+func (c *Converter) Int32(val any) (int32, error) { return IntOfWith[int32](c, val) }
+
 // MustInt32 将 val 转换成 int32 类型或是在无法转换的情况下返回 def 参数
 func MustInt32(val any, def ...int32) int32 { return MustIntOf(val, def...) }
 
 // Float64 将 val 转换成 float64 类型或是在无法转换的情况下返回 error
-func Float64(val any) (float64, error) {
+//
+// 与 [IntOf] 相同，字符串中的全角数字以及其它 Unicode 十进制数字会被当作对应的 ASCII 数字。
+func Float64(val any) (float64, error) { return defaultConverter.Float64(val) }
+
+// Float64 将 val 转换成 float64 类型或是在无法转换的情况下返回 error
+func (c *Converter) Float64(val any) (float64, error) {
 	switch ret := val.(type) {
 	case float64:
 		return ret, nil
 	case int:
-		return float64(ret), nil
+		return c.int2Float64(ret, int64(ret))
 	case int8:
 		return float64(ret), nil
 	case int32:
 		return float64(ret), nil
 	case int64:
-		return float64(ret), nil
+		return c.int2Float64(ret, ret)
 	case uint:
-		return float64(ret), nil
+		return c.uint2Float64(ret, uint64(ret))
 	case uint8:
 		return float64(ret), nil
 	case uint32:
 		return float64(ret), nil
 	case uint64:
-		return float64(ret), nil
+		return c.uint2Float64(ret, ret)
 	case float32:
 		return float64(ret), nil
 	case bool:
@@ -181,22 +263,58 @@ func Float64(val any) (float64, error) {
 		}
 		return 0.0, nil
 	case []byte:
-		val, err := strconv.ParseFloat(string(ret), 64)
-		if err == nil {
-			return float64(val), nil
-		}
-		return -1, typeError(val, "float64")
+		return c.str2Float64(ret, string(ret))
 	case string:
-		val, err := strconv.ParseFloat(ret, 64)
-		if err == nil {
-			return float64(val), nil
-		}
-		return -1, typeError(val, "float64")
+		return c.str2Float64(ret, ret)
 	default:
+		if v, ok := indirect(val); ok {
+			if v == nil {
+				return 0, c.nilError(val, "float64")
+			}
+			return c.Float64(v)
+		}
+
+		if u, ok := underlying(val); ok {
+			ret, err := c.Float64(u)
+			return ret, replaceErrorValue(err, val)
+		}
 		return -1, typeError(ret, "float64")
 	}
 }
 
+// 将整数 i 转换成 float64，严格模式下无法精确表示的值将返回错误。
+// val 为 i 的原始值，仅用于输出错误信息。
+func (c *Converter) int2Float64(val any, i int64) (float64, error) {
+	f := float64(i)
+	// float64(math.MaxInt64) 会被舍入为 2^63，无法再转换回 int64。
+	if c.strict && (f >= math.MaxInt64 || int64(f) != i) {
+		return -1, newError(val, "float64", ErrPrecision, nil)
+	}
+	return f, nil
+}
+
+// 将整数 i 转换成 float64，严格模式下无法精确表示的值将返回错误。
+// val 为 i 的原始值，仅用于输出错误信息。
+func (c *Converter) uint2Float64(val any, i uint64) (float64, error) {
+	f := float64(i)
+	if c.strict && (f >= math.MaxUint64 || uint64(f) != i) {
+		return -1, newError(val, "float64", ErrPrecision, nil)
+	}
+	return f, nil
+}
+
+// 将字符串 str 转换成 float64，val 为 str 的原始值，仅用于输出错误信息。
+func (c *Converter) str2Float64(val any, str string) (float64, error) {
+	ret, err := strconv.ParseFloat(c.normalizeNumber(str), 64)
+	if err == nil {
+		return ret, nil
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return -1, newError(val, "float64", ErrRange, err)
+	}
+	return -1, syntaxError(val, "float64", err)
+}
+
 // MustFloat64 将 val 转换成 float64 类型或是在无法转换的情况下返回 def 参数
 func MustFloat64(val any, def ...float64) float64 {
 	if ret, err := Float64(val); err == nil {
@@ -206,12 +324,32 @@ func MustFloat64(val any, def ...float64) float64 {
 }
 
 // Float32 将 val 转换成 float32 类型或是在无法转换的情况下返回 error
-func Float32(val any) (float32, error) {
-	ret, err := Float64(val)
+func Float32(val any) (float32, error) { return defaultConverter.Float32(val) }
+
+// Float32 将 val 转换成 float32 类型或是在无法转换的情况下返回 error
+func (c *Converter) Float32(val any) (float32, error) {
+	ret, err := c.Float64(val)
 	if err != nil {
 		return -1.0, err
 	}
-	return float32(ret), nil
+
+	f := float32(ret)
+	if !c.strict || math.IsNaN(ret) {
+		return f, nil
+	}
+
+	if math.IsInf(float64(f), 0) && !math.IsInf(ret, 0) {
+		return -1.0, rangeError(val, "float32")
+	}
+
+	switch val.(type) {
+	case string, []byte: // 字符串本身就是十进制的近似值，只要在取值范围之内即可。
+	default:
+		if float64(f) != ret {
+			return -1.0, newError(val, "float32", ErrPrecision, nil)
+		}
+	}
+	return f, nil
 }
 
 // MustFloat32 将 val 转换成 float32 类型或是在无法转换的情况下返回 def 参数
@@ -225,7 +363,10 @@ func MustFloat32(val any, def ...float32) float32 {
 // String 将 val 转换成 string 类型或是在无法转换的情况下返回 error
 //
 // NOTE: fmt.Stringer, ret.Error 和 encoding.TextMarshaler 都将被正确转换成字符串。
-func String(val any) (string, error) {
+func String(val any) (string, error) { return defaultConverter.String(val) }
+
+// String 将 val 转换成 string 类型或是在无法转换的情况下返回 error
+func (c *Converter) String(val any) (string, error) {
 	switch ret := val.(type) {
 	case string:
 		return ret, nil
@@ -242,19 +383,26 @@ func String(val any) (string, error) {
 	case int32:
 		return strconv.FormatInt(int64(ret), 10), nil
 	case uint:
-		return strconv.FormatInt(int64(ret), 10), nil
+		return strconv.FormatUint(uint64(ret), 10), nil
 	case uint8:
-		return strconv.FormatInt(int64(ret), 10), nil
+		return strconv.FormatUint(uint64(ret), 10), nil
 	case uint32:
-		return strconv.FormatInt(int64(ret), 10), nil
+		return strconv.FormatUint(uint64(ret), 10), nil
 	case uint64:
-		return strconv.FormatInt(int64(ret), 10), nil
+		return strconv.FormatUint(ret, 10), nil
 	case float32:
-		return strconv.FormatFloat(float64(ret), 'f', -1, 32), nil
+		return strconv.FormatFloat(float64(ret), c.floatFormat, c.stringPrecision, 32), nil
 	case float64:
-		return strconv.FormatFloat(ret, 'f', -1, 64), nil
+		return strconv.FormatFloat(ret, c.floatFormat, c.stringPrecision, 64), nil
 	case bool:
 		return strconv.FormatBool(ret), nil
+	case time.Time:
+		return c.formatTime(ret), nil
+	case *time.Time: // 需要在 fmt.Stringer 之前处理，否则 WithTimeFormat 对指针无效。
+		if ret == nil {
+			return "", c.nilError(val, "string")
+		}
+		return c.formatTime(*ret), nil
 	case fmt.Stringer:
 		return ret.String(), nil
 	case error:
@@ -266,6 +414,17 @@ func String(val any) (string, error) {
 		}
 		return string(v), nil
 	default:
+		if v, ok := indirect(val); ok {
+			if v == nil {
+				return "", c.nilError(val, "string")
+			}
+			return c.String(v)
+		}
+
+		if u, ok := underlying(val); ok {
+			ret, err := c.String(u)
+			return ret, replaceErrorValue(err, val)
+		}
 		return "", typeError(ret, "string")
 	}
 }
@@ -279,7 +438,10 @@ func MustString(val any, def ...string) string {
 }
 
 // Bytes 将 val 转换成 []byte 类型或是在无法转换的情况下返回 error
-func Bytes(val any) ([]byte, error) {
+func Bytes(val any) ([]byte, error) { return defaultConverter.Bytes(val) }
+
+// Bytes 将 val 转换成 []byte 类型或是在无法转换的情况下返回 error
+func (c *Converter) Bytes(val any) ([]byte, error) {
 	switch ret := val.(type) {
 	case []byte:
 		return ret, nil
@@ -294,20 +456,33 @@ func Bytes(val any) ([]byte, error) {
 	case int32:
 		return []byte(strconv.FormatInt(int64(ret), 10)), nil
 	case uint:
-		return []byte(strconv.FormatInt(int64(ret), 10)), nil
+		return []byte(strconv.FormatUint(uint64(ret), 10)), nil
 	case uint8:
-		return []byte(strconv.FormatInt(int64(ret), 10)), nil
+		return []byte(strconv.FormatUint(uint64(ret), 10)), nil
 	case uint32:
-		return []byte(strconv.FormatInt(int64(ret), 10)), nil
+		return []byte(strconv.FormatUint(uint64(ret), 10)), nil
 	case uint64:
-		return []byte(strconv.FormatInt(int64(ret), 10)), nil
+		return []byte(strconv.FormatUint(ret, 10)), nil
 	case float32:
-		return []byte(strconv.FormatFloat(float64(ret), 'f', 5, 32)), nil
+		return []byte(strconv.FormatFloat(float64(ret), c.floatFormat, c.bytesPrecision, 32)), nil
 	case float64:
-		return []byte(strconv.FormatFloat(ret, 'f', 5, 64)), nil
+		return []byte(strconv.FormatFloat(ret, c.floatFormat, c.bytesPrecision, 64)), nil
 	case bool:
 		return []byte(strconv.FormatBool(ret)), nil
+	case time.Time:
+		return []byte(c.formatTime(ret)), nil
 	default:
+		if v, ok := indirect(val); ok {
+			if v == nil {
+				return nil, c.nilError(val, "[]byte")
+			}
+			return c.Bytes(v)
+		}
+
+		if u, ok := underlying(val); ok {
+			ret, err := c.Bytes(u)
+			return ret, replaceErrorValue(err, val)
+		}
 		return nil, typeError(ret, "[]byte")
 	}
 }
@@ -332,22 +507,38 @@ func MustBytes(val any, def ...[]byte) []byte {
 // "123" 返回 []interface{}{rune(49),rune(50),rune(51)}
 func Slice(val any) ([]any, error) { return SliceOf[any](val) }
 
+// Slice 将 val 转换成 slice 类型或是在无法转换的情况下返回 error
+func (c *Converter) Slice(val any) ([]any, error) { return SliceOfWith[any](c, val) }
+
 // MustSlice 将 val 转换成 slice 类型或是在无法转换的情况下返回 def 参数
 func MustSlice(val any, def ...[]any) []any { return MustSliceOf(val, def...) }
 
 // SliceOf 将 val 转换成 []T
 //
 // 只要 val 是数组或是字符串，且其元素能转换成 T 类型即可。
-func SliceOf[T any](val any) ([]T, error) {
+// 数值元素超出 T 的取值范围时返回 [ErrRange]，而不是溢出之后的值。
+func SliceOf[T any](val any) ([]T, error) { return SliceOfWith[T](defaultConverter, val) }
+
+// SliceOfWith 采用 c 的转换规则实现 [SliceOf]
+func SliceOfWith[T any](c *Converter, val any) ([]T, error) {
 	srcV := reflect.ValueOf(val)
 	switch srcV.Kind() {
 	case reflect.String:
 		dest := make([]T, srcV.Len())
 		destV := reflect.ValueOf(dest)
 		destT := destV.Type().Elem()
-		if reflect.TypeOf('a').ConvertibleTo(destT) { // TODO(go1.22) 可用 reflect.TypeFor 代替 typeOf
-			for i := 0; i < srcV.Len(); i++ {
-				destV.Index(i).Set(srcV.Index(i).Convert(destT))
+
+		// 字符串按字节处理，数值类型需要经过 Value 以检测溢出，比如 é 的字节无法保存在 int8 中；
+		// 其它类型则将字节当作字符进行转换，比如 []string。
+		for i := 0; i < srcV.Len(); i++ {
+			v := srcV.Index(i)
+			switch {
+			case isNumberKind(destT.Kind()) || lookupConverter(v.Type(), destT) != nil:
+				if err := c.Value(v.Interface(), destV.Index(i)); err != nil {
+					return nil, withIndex(err, i)
+				}
+			case v.Type().ConvertibleTo(destT):
+				destV.Index(i).Set(v.Convert(destT))
 			}
 		}
 
@@ -360,11 +551,12 @@ func SliceOf[T any](val any) ([]T, error) {
 		for i := 0; i < srcV.Len(); i++ {
 			v := srcV.Index(i)
 
-			if v.Type().ConvertibleTo(destT) { // srcV 的项类型是确定的，比如 []any，可以包含任何类型。
+			// srcV 的项类型是确定的，比如 []any，可以包含任何类型。
+			if directConvertible(v.Type(), destT) && lookupConverter(v.Type(), destT) == nil {
 				destV.Index(i).Set(v.Convert(destT))
 			} else {
-				if err := Value(v.Interface(), destV.Index(i)); err != nil {
-					return nil, err
+				if err := c.Value(v.Interface(), destV.Index(i)); err != nil {
+					return nil, withIndex(err, i)
 				}
 			}
 		}
@@ -375,6 +567,29 @@ func SliceOf[T any](val any) ([]T, error) {
 	}
 }
 
+// 判断 src 是否可以直接通过 [reflect.Value.Convert] 转换成 dest
+//
+// 数值之间的 Convert 会在溢出时静默回绕，比如 int(300) 转换成 int8 为 44，
+// 而整数转换成字符串时会被当作码点，所以涉及数值的转换只有类型相同或是 dest 为接口时才可以直接转换，
+// 其它情况需要经过 [Converter.Value]。
+func directConvertible(src, dest reflect.Type) bool {
+	if src == dest || dest.Kind() == reflect.Interface {
+		return src.ConvertibleTo(dest)
+	}
+	return !isNumberKind(src.Kind()) && !isNumberKind(dest.Kind()) && src.ConvertibleTo(dest)
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	default:
+		return false
+	}
+}
+
 // MustSliceOf 将 val 转换成 slice 类型或是在无法转换的情况下返回 def 参数
 func MustSliceOf[T any](val any, def ...[]T) []T {
 	if ret, err := SliceOf[T](val); err == nil {
@@ -388,7 +603,36 @@ func MustSliceOf[T any](val any, def ...[]T) []T {
 	return def[0]
 }
 
-func toInt64(val any) (int64, error) {
+// MapOf 将 val 转换成 map[K]V
+//
+// val 必须是 map 类型，其键名和键值分别通过 [Value] 转换成 K 和 V 类型。
+func MapOf[K comparable, V any](val any) (map[K]V, error) {
+	return MapOfWith[K, V](defaultConverter, val)
+}
+
+// MapOfWith 采用 c 的转换规则实现 [MapOf]
+func MapOfWith[K comparable, V any](c *Converter, val any) (map[K]V, error) {
+	var ret map[K]V
+	if err := c.Value(val, reflect.ValueOf(&ret)); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// MustMapOf 将 val 转换成 map 类型或是在无法转换的情况下返回 def 参数
+func MustMapOf[K comparable, V any](val any, def ...map[K]V) map[K]V {
+	if ret, err := MapOf[K, V](val); err == nil {
+		return ret
+	}
+
+	if len(def) == 0 {
+		panic(typeError(val, "map"))
+	}
+
+	return def[0]
+}
+
+func (c *Converter) toInt64(val any) (int64, error) {
 	switch ret := val.(type) {
 	case int64:
 		return ret, nil
@@ -399,75 +643,240 @@ func toInt64(val any) (int64, error) {
 	case int32:
 		return int64(ret), nil
 	case uint:
+		if uint64(ret) > math.MaxInt64 {
+			return -1, rangeError(ret, "int64")
+		}
 		return int64(ret), nil
 	case uint8:
 		return int64(ret), nil
 	case uint32:
 		return int64(ret), nil
 	case uint64:
+		if ret > math.MaxInt64 {
+			return -1, rangeError(ret, "int64")
+		}
 		return int64(ret), nil
 	case float32:
-		return int64(ret), nil
+		return c.float2Int64(ret, float64(ret))
 	case float64:
-		return int64(ret), nil
+		return c.float2Int64(ret, ret)
 	case bool:
 		if ret {
 			return 1, nil
 		}
 		return 0, nil
 	case []byte:
-		if bytes.ContainsRune(ret, '.') { // 浮点
-			if val, err := strconv.ParseFloat(string(ret), 64); err == nil {
-				return int64(val), nil
+		return c.str2Int64(ret, string(ret))
+	case string:
+		return c.str2Int64(ret, ret)
+	case time.Time:
+		return ret.Unix(), nil
+	default:
+		if v, ok := indirect(val); ok {
+			if v == nil {
+				return 0, c.nilError(val, "int64")
 			}
-			return 0, typeError(ret, "uint64")
+			return c.toInt64(v)
 		}
 
-		val, err := strconv.ParseInt(string(ret), 10, 64)
-		if err == nil {
-			return int64(val), nil
+		if u, ok := underlying(val); ok {
+			ret, err := c.toInt64(u)
+			return ret, replaceErrorValue(err, val)
 		}
 		return -1, typeError(ret, "int64")
-	case string:
-		if strings.ContainsRune(ret, '.') { // 浮点
-			if val, err := strconv.ParseFloat(string(ret), 64); err == nil {
-				return int64(val), nil
+	}
+}
+
+// 将字符串 str 转换成 int64，val 为 str 的原始值，仅用于输出错误信息。
+func (c *Converter) str2Int64(val any, str string) (int64, error) {
+	return c.parseInt64(val, c.normalizeNumber(str))
+}
+
+// 将已经由 c.normalizeNumber 处理过的字符串 str 转换成 int64
+func (c *Converter) parseInt64(val any, str string) (int64, error) {
+	str, base := c.intBase(str)
+	if base == 10 && strings.ContainsAny(str, ".eE") { // 浮点或是科学计数法
+		f, err := strconv.ParseFloat(str, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, newError(val, "int64", ErrRange, err)
+		} else if err != nil {
+			return 0, syntaxError(val, "int64", err)
+		}
+		return c.float2Int64(val, f)
+	}
+
+	ret, err := strconv.ParseInt(str, base, 64)
+	if err == nil {
+		return ret, nil
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return -1, newError(val, "int64", ErrRange, err)
+	}
+	return -1, syntaxError(val, "int64", err)
+}
+
+// 根据 c.numberFormat 将 str 转换成 strconv 可以解析的格式
+//
+// 除了由 [normalizeDigits] 处理的字符之外，还会去掉首尾的空白字符、货币符号和千位分隔符，并将小数点替换为 .。
+func (c *Converter) normalizeNumber(str string) string {
+	str = normalizeDigits(str)
+
+	f := c.numberFormat
+	if f == nil {
+		return str
+	}
+
+	str = strings.TrimSpace(str)
+
+	var sign string
+	if str != "" && (str[0] == '-' || str[0] == '+') {
+		sign, str = str[:1], strings.TrimSpace(str[1:])
+	}
+	for _, symbol := range f.Currency {
+		if symbol == "" {
+			continue
+		}
+		if s := strings.TrimPrefix(str, symbol); s != str {
+			str = strings.TrimSpace(s)
+			break
+		}
+		if s := strings.TrimSuffix(str, symbol); s != str {
+			str = strings.TrimSpace(s)
+			break
+		}
+	}
+	if sign == "" && str != "" && (str[0] == '-' || str[0] == '+') { // $-1 之类的格式
+		sign, str = str[:1], strings.TrimSpace(str[1:])
+	}
+
+	if f.Group != "" {
+		str = strings.ReplaceAll(str, f.Group, "")
+	}
+	if f.Decimal != "" && f.Decimal != "." {
+		str = strings.Replace(str, f.Decimal, ".", 1)
+	}
+	return sign + str
+}
+
+// 将 str 中的 Unicode 数字和全角字符转换成对应的 ASCII 字符
+//
+// 包括全角、阿拉伯-印度、天城文等所有 Unicode 十进制数字，比如 １２３ 和 ١٢٣ 都转换成 123；
+// 全角的标点和字母，比如 － 和 ．；全角空格以及数学中的减号 −。
+func normalizeDigits(str string) string {
+	ascii := true
+	for i := 0; i < len(str); i++ {
+		if str[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return str
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r < utf8.RuneSelf:
+			return r
+		case r >= '！' && r <= '～': // U+FF01 - U+FF5E 与 ASCII 中的 ! 至 ~ 一一对应
+			return r - '！' + '!'
+		case r == '\u3000': // 全角空格
+			return ' '
+		case r == '−': // U+2212
+			return '-'
+		case unicode.IsDigit(r):
+			// Unicode 保证十进制数字总是以 0 至 9 的顺序连续排列，
+			// 所以前面连续的数字个数即是其数值。
+			n := 0
+			for unicode.IsDigit(r - rune(n) - 1) {
+				n++
 			}
-			return 0, typeError(ret, "uint64")
+			return '0' + rune(n%10)
+		default:
+			return r
+		}
+	}, str)
+}
+
+// 分析整数字符串 str 的进制
+//
+// 以 0x、0o 或 0b 开头时返回 0，由 strconv 按 Go 字面量的规则解析；
+// 否则去掉数字之间作为分隔符的下划线并返回 10。指定了 [WithDecimal] 时原样返回 str 和 10。
+//
+// 与 Go 字面量不同，以 0 开头的数字依然被当作十进制。
+func (c *Converter) intBase(str string) (string, int) {
+	if c.decimal {
+		return str, 10
+	}
+
+	s := str
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	if len(s) > 2 && s[0] == '0' {
+		switch s[1] {
+		case 'x', 'X', 'o', 'O', 'b', 'B':
+			return str, 0
 		}
+	}
+
+	return trimDigitSeparator(str), 10
+}
+
+// 去掉 str 中作为数字分隔符的下划线
+//
+// 下划线只能出现在两个数字之间，否则原样返回 str，由之后的解析函数报错。
+func trimDigitSeparator(str string) string {
+	if !strings.ContainsRune(str, '_') {
+		return str
+	}
 
-		val, err := strconv.ParseInt(ret, 10, 64)
-		if err == nil {
-			return int64(val), nil
+	isDigit := func(b byte) bool { return b >= '0' && b <= '9' }
+	for i := 0; i < len(str); i++ {
+		if str[i] == '_' && (i == 0 || i == len(str)-1 || !isDigit(str[i-1]) || !isDigit(str[i+1])) {
+			return str
 		}
-		return -1, typeError(ret, "int64")
-	default:
-		return -1, typeError(ret, "int64")
 	}
+	return strings.ReplaceAll(str, "_", "")
 }
 
-func toUint64(val any) (uint64, error) {
+// 将浮点数 f 转换为 int64，小数部分由 c.truncation 处理。
+// val 为 f 的原始值，仅用于输出错误信息。
+func (c *Converter) float2Int64(val any, f float64) (int64, error) {
+	f, err := c.truncate(val, f, "int64")
+	if err != nil {
+		return -1, err
+	}
+
+	// float64(math.MaxInt64) 会被舍入为 2^63，所以上限需要用 >= 判断。
+	if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return -1, rangeError(val, "int64")
+	}
+	return int64(f), nil
+}
+
+func (c *Converter) toUint64(val any) (uint64, error) {
 	switch ret := val.(type) {
 	case uint64:
 		return ret, nil
 	case int:
 		if ret < 0 {
-			return 0, typeError(ret, "uint64")
+			return 0, negativeError(ret, "uint64")
 		}
 		return uint64(ret), nil
 	case int8:
 		if ret < 0 {
-			return 0, typeError(ret, "uint64")
+			return 0, negativeError(ret, "uint64")
 		}
 		return uint64(ret), nil
 	case int32:
 		if ret < 0 {
-			return 0, typeError(ret, "uint64")
+			return 0, negativeError(ret, "uint64")
 		}
 		return uint64(ret), nil
 	case int64:
 		if ret < 0 {
-			return 0, typeError(ret, "uint64")
+			return 0, negativeError(ret, "uint64")
 		}
 		return uint64(ret), nil
 	case uint:
@@ -477,47 +886,188 @@ func toUint64(val any) (uint64, error) {
 	case uint32:
 		return uint64(ret), nil
 	case float32:
-		if ret < 0 {
-			return 0, typeError(ret, "uint64")
-		}
-		return uint64(ret), nil
+		return c.float2Uint64(ret, float64(ret))
 	case float64:
-		if ret < 0 {
-			return 0, typeError(ret, "uint64")
-		}
-		return uint64(ret), nil
+		return c.float2Uint64(ret, ret)
 	case bool:
 		if ret {
 			return 1, nil
 		}
 		return 0, nil
 	case []byte:
-		if bytes.ContainsRune(ret, '.') { // 浮点
-			if val, err := strconv.ParseFloat(string(ret), 64); err == nil {
-				return uint64(val), nil
+		return c.str2Uint64(ret, string(ret))
+	case string:
+		return c.str2Uint64(ret, ret)
+	case time.Time:
+		if ret.Unix() < 0 {
+			return 0, negativeError(ret, "uint64")
+		}
+		return uint64(ret.Unix()), nil
+	default:
+		if v, ok := indirect(val); ok {
+			if v == nil {
+				return 0, c.nilError(val, "uint64")
 			}
-			return 0, typeError(ret, "uint64")
+			return c.toUint64(v)
 		}
 
-		if val, err := strconv.ParseUint(string(ret), 10, 64); err == nil {
-			return uint64(val), nil
+		if u, ok := underlying(val); ok {
+			ret, err := c.toUint64(u)
+			return ret, replaceErrorValue(err, val)
 		}
 		return 0, typeError(ret, "uint64")
-	case string:
-		if strings.ContainsRune(ret, '.') { // 浮点
-			if val, err := strconv.ParseFloat(string(ret), 64); err == nil {
-				return uint64(val), nil
-			}
-			return 0, typeError(ret, "uint64")
+	}
+}
+
+// 将字符串 str 转换成 uint64，val 为 str 的原始值，仅用于输出错误信息。
+func (c *Converter) str2Uint64(val any, str string) (uint64, error) {
+	str = c.normalizeNumber(str)
+	str, base := c.intBase(str)
+	if base == 10 && strings.ContainsAny(str, ".eE") { // 浮点或是科学计数法
+		f, err := strconv.ParseFloat(str, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, newError(val, "uint64", ErrRange, err)
+		} else if err != nil {
+			return 0, syntaxError(val, "uint64", err)
 		}
+		return c.float2Uint64(val, f)
+	}
 
-		if val, err := strconv.ParseUint(ret, 10, 64); err == nil {
-			return uint64(val), nil
+	if strings.HasPrefix(str, "-") { // 与数值类型相同，负数返回 ErrNegative。
+		n, err := strconv.ParseInt(str, base, 64)
+		switch {
+		case err == nil && n == 0:
+			return 0, nil
+		case err == nil || errors.Is(err, strconv.ErrRange):
+			return 0, negativeError(val, "uint64")
+		default:
+			return 0, syntaxError(val, "uint64", err)
 		}
-		return 0, typeError(ret, "uint64")
+	}
+
+	ret, err := strconv.ParseUint(str, base, 64)
+	if err == nil {
+		return ret, nil
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, newError(val, "uint64", ErrRange, err)
+	}
+	return 0, syntaxError(val, "uint64", err)
+}
+
+// 将浮点数 f 转换为 uint64，小数部分由 c.truncation 处理。
+// val 为 f 的原始值，仅用于输出错误信息。
+func (c *Converter) float2Uint64(val any, f float64) (uint64, error) {
+	if f < 0 {
+		return 0, negativeError(val, "uint64")
+	}
+
+	f, err := c.truncate(val, f, "uint64")
+	if err != nil {
+		return 0, err
+	}
+
+	// float64(math.MaxUint64) 会被舍入为 2^64，所以上限需要用 >= 判断。
+	if math.IsNaN(f) || f >= math.MaxUint64 {
+		return 0, rangeError(val, "uint64")
+	}
+	return uint64(f), nil
+}
+
+// 根据 c.truncation 处理 f 的小数部分，严格模式下始终为 TruncateError。
+//
+// val 为 f 的原始值；t 为目标类型，两者仅用于输出错误信息。
+func (c *Converter) truncate(val any, f float64, t string) (float64, error) {
+	truncation := c.truncation
+	if c.strict {
+		truncation = TruncateError
+	}
+
+	switch truncation {
+	case TruncateRound:
+		return math.Round(f), nil
+	case TruncateFloor:
+		return math.Floor(f), nil
+	case TruncateCeil:
+		return math.Ceil(f), nil
+	case TruncateError:
+		if f != math.Trunc(f) {
+			return 0, newError(val, t, ErrPrecision, nil)
+		}
+		return f, nil
 	default:
-		return 0, typeError(ret, "uint64")
+		return math.Trunc(f), nil
+	}
+}
+
+// 解引用 val 中的指针和接口
+//
+// 如果 val 为 nil 或是指针，ok 返回 true，u 为指针指向的值，
+// 空指针或是 val 为 nil 时 u 为 nil。
+func indirect(val any) (u any, ok bool) {
+	if val == nil {
+		return nil, true
+	}
+
+	v := reflect.ValueOf(val)
+	if v.Kind() != reflect.Pointer {
+		return val, false
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, true
+		}
+		v = v.Elem()
+	}
+	return v.Interface(), true
+}
+
+// 根据 c.nilAsZero 返回 nil 值的错误信息
+//
+// val 当前值；t 目标类型。
+func (c *Converter) nilError(val any, t string) error {
+	if c.nilAsZero {
+		return nil
+	}
+	return newError(val, t, ErrNil, nil)
+}
+
+// 根据 val 的 [reflect.Kind] 将其转换成对应的基本类型
+//
+// 比如 type Port uint16 会被转换成 uint64，time.Duration 会被转换成 int64。
+// 返回的值只会是 bool、int64、uint64、float64、string 和 []byte 之一，
+// 无法转换时 ok 返回 false。
+func underlying(val any) (u any, ok bool) {
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		return v.String(), true
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes(), true
+		}
+	}
+	return nil, false
+}
+
+// 将 err 中的值替换为 val
+//
+// 由 [underlying] 转换之后的值产生的错误，需要将其中的值还原为原始值。
+func replaceErrorValue(err error, val any) error {
+	if e, ok := err.(*Error); ok {
+		e.Value = val
+		e.Source = fmt.Sprintf("%T", val)
 	}
+	return err
 }
 
 // MustIntOf 将 val 转换成 T 类型或是在无法转换的情况下返回 def 参数
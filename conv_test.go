@@ -5,7 +5,12 @@
 package conv
 
 import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/issue9/assert/v4"
 )
@@ -271,6 +276,133 @@ func TestInt64(t *testing.T) {
 	fn("123", 123)
 }
 
+func TestIntOf(t *testing.T) {
+	a := assert.New(t, false)
+
+	type myInt int16
+
+	v1, err := IntOf[int8]("127")
+	a.NotError(err).Equal(v1, int8(127))
+
+	v2, err := IntOf[myInt](-32768)
+	a.NotError(err).Equal(v2, myInt(-32768))
+
+	_, err = IntOf[int8](300)
+	a.ErrorIs(err, ErrRange)
+
+	_, err = IntOf[int8]("-129")
+	a.ErrorIs(err, ErrRange)
+
+	_, err = IntOf[myInt](uint32(40000))
+	a.ErrorIs(err, ErrRange)
+
+	_, err = IntOf[int64](uint64(math.MaxUint64))
+	a.ErrorIs(err, ErrRange)
+
+	_, err = IntOf[int64]("9223372036854775808")
+	a.ErrorIs(err, ErrRange)
+
+	_, err = IntOf[int64](1e19)
+	a.ErrorIs(err, ErrRange)
+
+	_, err = IntOf[int64](math.NaN())
+	a.ErrorIs(err, ErrRange)
+
+	// 非范围错误
+	_, err = IntOf[int8]("abc")
+	a.Error(err).False(errors.Is(err, ErrRange))
+
+	// 进制前缀、下划线和科学计数法
+	for str, want := range map[string]int64{
+		"0x1F":      31,
+		"-0X1f":     -31,
+		"0o755":     493,
+		"0b1010":    10,
+		"+0b1":      1,
+		"0x_ff":     255,
+		"1_000_000": 1000000,
+		"-1_000":    -1000,
+		"010":       10,
+		"1e6":       1000000,
+		"1.5E3":     1500,
+		"-2e2":      -200,
+		"1_000.5":   1000,
+	} {
+		v, err := IntOf[int64](str)
+		a.NotError(err, str).Equal(v, want, str)
+	}
+
+	for _, str := range []string{"_1", "1_", "1__0", "0x", "0xg", "0b2", "1e"} {
+		_, err = IntOf[int64](str)
+		a.ErrorIs(err, ErrSyntax, str)
+	}
+
+	_, err = IntOf[int8]("0x80")
+	a.ErrorIs(err, ErrRange)
+
+	_, err = IntOf[int64]("1e19")
+	a.ErrorIs(err, ErrRange)
+
+	_, err = IntOf[int64]("1e400")
+	a.ErrorIs(err, ErrRange).ErrorIs(err, strconv.ErrRange)
+
+	_, err = UintOf[uint64]("1e400")
+	a.ErrorIs(err, ErrRange).ErrorIs(err, strconv.ErrRange)
+
+	_, err = UintOf[uint64]("1e")
+	a.ErrorIs(err, ErrSyntax)
+
+	// []byte
+	v3, err := IntOf[int]([]byte("0x10"))
+	a.NotError(err).Equal(v3, 16)
+}
+
+func TestUintOf(t *testing.T) {
+	a := assert.New(t, false)
+
+	type port uint16
+
+	v1, err := UintOf[uint8]("255")
+	a.NotError(err).Equal(v1, uint8(255))
+
+	v2, err := UintOf[port](8080)
+	a.NotError(err).Equal(v2, port(8080))
+
+	_, err = UintOf[uint8](256)
+	a.ErrorIs(err, ErrRange)
+
+	_, err = UintOf[port]("65536")
+	a.ErrorIs(err, ErrRange)
+
+	_, err = UintOf[uint64]("18446744073709551616")
+	a.ErrorIs(err, ErrRange)
+
+	_, err = UintOf[uint64](1e20)
+	a.ErrorIs(err, ErrRange)
+
+	// 负数
+	_, err = UintOf[uint8]("-1.5")
+	a.Error(err).False(errors.Is(err, ErrRange))
+
+	// 进制前缀、下划线和科学计数法
+	for str, want := range map[string]uint64{
+		"0xFFFFFFFFFFFFFFFF": math.MaxUint64,
+		"0o17":               15,
+		"0b11":               3,
+		"8_080":              8080,
+		"2e3":                2000,
+	} {
+		v, err := UintOf[uint64](str)
+		a.NotError(err, str).Equal(v, want, str)
+	}
+
+	_, err = UintOf[uint8]("0x100")
+	a.ErrorIs(err, ErrRange)
+
+	_, err = UintOf[uint8]("-1e2")
+	a.ErrorIs(err, ErrNegative)
+}
+
 func TestUint(t *testing.T) {
 	a := assert.New(t, false)
 
@@ -390,7 +522,32 @@ func TestSliceOf(t *testing.T) {
 
 	// int ==> int8，溢出。
 	ret4, err := SliceOf[int8]([]int{1000, 2, 3})
-	a.NotError(err).Equal(ret4, []int8{-24, 2, 3})
+	a.ErrorIs(err, ErrRange).Nil(ret4)
+	var e *Error
+	a.True(errors.As(err, &e)).Equal(e.Path, "[0]")
+
+	_, err = SliceOf[int8]([]int{300})
+	a.ErrorIs(err, ErrRange)
+
+	_, err = SliceOf[uint8]([]int{-1, 256})
+	a.ErrorIs(err, ErrNegative)
+
+	_, err = SliceOf[uint8]([]int{1, 256})
+	a.ErrorIs(err, ErrRange)
+
+	_, err = SliceOf[int64]([]uint64{math.MaxUint64})
+	a.ErrorIs(err, ErrRange)
+
+	ret9, err := SliceOf[uint8]([2]int{0, 255})
+	a.NotError(err).Equal(ret9, []uint8{0, 255})
+
+	// int ==> string，不再被当作码点。
+	ret10, err := SliceOf[string]([]int{65, 66})
+	a.NotError(err).Equal(ret10, []string{"65", "66"})
+
+	// float64 ==> int
+	ret11, err := SliceOf[int]([]float64{1.5, -2})
+	a.NotError(err).Equal(ret11, []int{1, -2})
 
 	// []string ==> int
 	ret5, err := SliceOf[int]([]string{"1", "2", "3"})
@@ -407,4 +564,218 @@ func TestSliceOf(t *testing.T) {
 	// string ==> byte
 	ret8, err := SliceOf[byte]("123")
 	a.NotError(err).Equal(ret8, []byte{'1', '2', '3'})
+
+	// string ==> int8，溢出。
+	_, err = SliceOf[int8]("é")
+	a.ErrorIs(err, ErrRange).True(errors.As(err, &e)).Equal(e.Path, "[0]")
+
+	// string ==> string
+	ret12, err := SliceOf[string]("ab")
+	a.NotError(err).Equal(ret12, []string{"a", "b"})
+}
+
+func TestUnderlying(t *testing.T) {
+	a := assert.New(t, false)
+
+	type port uint16
+	type name string
+	type flag bool
+	type ratio float32
+	type raw []byte
+
+	a.Equal(MustInt(int16(-5)), -5)
+	a.Equal(MustInt(uint16(5)), 5)
+	a.Equal(MustInt(uintptr(5)), 5)
+	a.Equal(MustInt(port(80)), 80)
+	a.Equal(MustInt64(time.Second), int64(time.Second))
+	a.Equal(MustInt(name("12")), 12)
+	a.Equal(MustUint(port(80)), 80)
+	a.Equal(MustUint8(raw("12")), 12)
+	a.Equal(MustUint(int16(5)), 5)
+	a.Equal(MustFloat64(ratio(0.5)), 0.5)
+	a.Equal(MustFloat64(int16(-5)), -5.0)
+	a.True(MustBool(flag(true)))
+	a.True(MustBool(uint16(1)))
+	a.Equal(MustString(port(80)), "80")
+	a.Equal(MustString(name("n")), "n")
+	a.Equal(MustString(raw("r")), "r")
+	a.Equal(MustString(time.Second), "1s") // fmt.Stringer 优先
+	a.Equal(MustBytes(uintptr(5)), []byte("5"))
+	a.Equal(MustBytes(flag(false)), []byte("false"))
+	a.Equal(MustString(uint64(math.MaxUint64)), "18446744073709551615")
+	a.Equal(MustBytes(uint64(math.MaxUint64)), []byte("18446744073709551615"))
+
+	_, err := Uint(int16(-5))
+	a.ErrorIs(err, ErrNegative)
+	var ce *Error
+	a.True(errors.As(err, &ce)).Equal(ce.Value, int16(-5)).Equal(ce.Source, "int16")
+
+	_, err = UintOf[uint8](port(300))
+	a.ErrorIs(err, ErrRange)
+
+	_, err = Int(name("x"))
+	a.ErrorIs(err, ErrSyntax).True(errors.As(err, &ce)).Equal(ce.Source, "conv.name")
+
+	_, err = Int(struct{}{})
+	a.ErrorIs(err, ErrUnsupported)
+}
+
+func TestIndirect(t *testing.T) {
+	a := assert.New(t, false)
+
+	i := 5
+	pi := &i
+	s := "on"
+	var ai any = &s
+	var nilInt *int
+
+	a.Equal(MustInt(&i), 5)
+	a.Equal(MustInt(&pi), 5)
+	a.Equal(MustUint(&i), 5)
+	a.Equal(MustFloat64(&i), 5.0)
+	a.Equal(MustString(&i), "5")
+	a.Equal(MustBytes(&i), []byte("5"))
+	a.True(MustBool(&s))
+	a.True(MustBool(&ai))
+
+	_, err := Int(nilInt)
+	a.ErrorIs(err, ErrNil)
+	_, err = String(nil)
+	a.ErrorIs(err, ErrNil)
+	_, err = Bool(&nilInt)
+	a.ErrorIs(err, ErrNil)
+
+	c := New(WithNilAsZero())
+	v1, err := c.Int(nilInt)
+	a.NotError(err).Equal(v1, 0)
+	v2, err := c.String(nil)
+	a.NotError(err).Equal(v2, "")
+	v3, err := c.Bytes(nilInt)
+	a.NotError(err).Nil(v3)
+
+	// Value
+	t1 := 1
+	a.NotError(Value(&i, reflect.ValueOf(&t1))).Equal(t1, 5)
+	a.NotError(Value(nilInt, reflect.ValueOf(&t1))).Equal(t1, 0)
+	var t2 any
+	a.NotError(Value(&i, reflect.ValueOf(&t2))).Equal(t2, &i)
+	var t3 []int
+	a.NotError(Value(&[]string{"1"}, reflect.ValueOf(&t3))).Equal(t3, []int{1})
+}
+
+func TestMapOf(t *testing.T) {
+	a := assert.New(t, false)
+
+	m1, err := MapOf[string, int](map[string]any{"a": 1, "b": "2", "c": 3.0})
+	a.NotError(err).Equal(m1, map[string]int{"a": 1, "b": 2, "c": 3})
+
+	m2, err := MapOf[int, float64](map[string]string{"1": "1.5", "2": "2"})
+	a.NotError(err).Equal(m2, map[int]float64{1: 1.5, 2: 2})
+
+	m3, err := MapOf[string, []int](map[any]any{"a": []string{"1"}, 2: []any{2, "3"}})
+	a.NotError(err).Equal(m3, map[string][]int{"a": {1}, "2": {2, 3}})
+
+	m4, err := MapOf[string, int](&map[string]string{"a": "1"})
+	a.NotError(err).Equal(m4, map[string]int{"a": 1})
+
+	_, err = MapOf[string, int8](map[string]any{"port": 300})
+	a.ErrorIs(err, ErrRange)
+	var ce *Error
+	a.True(errors.As(err, &ce)).Equal(ce.Path, "port")
+
+	_, err = MapOf[int, int](map[string]any{"x": 1})
+	a.ErrorIs(err, ErrSyntax).True(errors.As(err, &ce)).Equal(ce.Path, "x")
+
+	_, err = MapOf[string, int]([]int{1})
+	a.ErrorIs(err, ErrUnsupported)
+}
+
+func TestMustMapOf(t *testing.T) {
+	a := assert.New(t, false)
+
+	def := map[string]int{"def": 1}
+	a.Equal(MustMapOf[string, int](map[string]string{"a": "1"}, def), map[string]int{"a": 1})
+	a.Equal(MustMapOf[string, int](map[string]string{"a": "x"}, def), def)
+	a.PanicString(func() {
+		MustMapOf[string, int](7)
+	}, "conv: int:7 无法转换成 map 类型")
+}
+
+func TestNormalizeDigits(t *testing.T) {
+	a := assert.New(t, false)
+
+	for src, want := range map[string]string{
+		"":      "",
+		"123":   "123",
+		"１２３":   "123",
+		"－５":    "-5",
+		"＋５":    "+5",
+		"３．１４":  "3.14",
+		"１，０００": "1,000",
+		"１ｅ３":   "1e3",
+		"０ｘ１Ｆ":  "0x1F",
+		"ｔｒｕｅ":  "true",
+		"　１２":   " 12",
+		"−12":   "-12",
+		"١٢٣":   "123", // 阿拉伯-印度数字
+		"۴۵۶":   "456", // 扩展阿拉伯-印度数字
+		"१२३":   "123", // 天城文
+		"๑๒":    "12",  // 泰文
+		"𝟏𝟐𝟗":   "129", // 数学粗体
+		"𝟶𝟿":    "09",  // 数学等宽
+		"一二三":   "一二三",
+		"12元":   "12元",
+		"ab cd": "ab cd",
+	} {
+		a.Equal(normalizeDigits(src), want, src)
+	}
+}
+
+func TestUnicodeDigits(t *testing.T) {
+	a := assert.New(t, false)
+
+	i, err := Int("１２３")
+	a.NotError(err).Equal(i, 123)
+
+	i, err = Int([]byte("－５"))
+	a.NotError(err).Equal(i, -5)
+
+	i, err = Int("١٢٣")
+	a.NotError(err).Equal(i, 123)
+
+	u, err := Uint64("१०२४")
+	a.NotError(err).Equal(u, uint64(1024))
+
+	_, err = Uint64("－５")
+	a.Error(err)
+
+	f, err := Float64("３．１４")
+	a.NotError(err).Equal(f, 3.14)
+
+	f, err = Float64("１ｅ３")
+	a.NotError(err).Equal(f, 1000)
+
+	b, err := Bool("ｔｒｕｅ")
+	a.NotError(err).True(b)
+
+	b, err = Bool("　０　")
+	a.NotError(err).False(b)
+
+	b, err = Bool("２")
+	a.NotError(err).True(b)
+
+	d, err := Duration("１ｈ３０ｍ")
+	a.NotError(err).Equal(d, 90*time.Minute)
+
+	tm, err := Time("２０２４－０１－０２")
+	a.NotError(err).True(tm.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+
+	c := New(WithNumberFormat(NumberFormat{Group: ",", Currency: []string{"¥"}}))
+	f, err = c.Float64("¥１，２３４．５")
+	a.NotError(err).Equal(f, 1234.5)
+
+	// 不改变错误信息中的原始值
+	_, err = Int("１ａ")
+	var e *Error
+	a.True(errors.As(err, &e)).Equal(e.Value, "１ａ")
 }
@@ -0,0 +1,298 @@
+// SPDX-FileCopyrightText: 2014-2026 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package conv
+
+import "time"
+
+// Truncation 浮点数转换成整数时对小数部分的处理方式
+type Truncation int8
+
+const (
+	TruncateTowardZero Truncation = iota // 向零截断，默认值。
+	TruncateRound                        // 四舍五入
+	TruncateFloor                        // 向下取整
+	TruncateCeil                         // 向上取整
+	TruncateError                        // 包含小数部分时返回 ErrPrecision
+)
+
+// NumberFormat 字符串中数值的格式
+//
+// 用于解析包含千位分隔符、货币符号等内容的数值字符串，比如 1,234.5、1.234,5 €。
+type NumberFormat struct {
+	// Group 千位分隔符
+	//
+	// 数值中所有的 Group 都会被删除，不检测其位置是否正确。为空表示不存在千位分隔符。
+	Group string
+
+	// Decimal 小数点
+	//
+	// 为空表示采用 .。
+	Decimal string
+
+	// Currency 可能出现在数值前后的货币符号，比如 $、€ 和 ¥ 等。
+	Currency []string
+}
+
+// Converter 类型转换器
+//
+// 包中的各个函数都是由一个默认的 Converter 实例实现的，
+// 如果需要改变转换的行为，可以通过 [New] 声明一个新的实例。
+type Converter struct {
+	strict    bool
+	nilAsZero bool
+
+	trueValues  []string
+	falseValues []string
+
+	floatFormat     byte
+	stringPrecision int
+	bytesPrecision  int
+
+	truncation   Truncation
+	decimal      bool
+	numberFormat *NumberFormat
+	durationUnit time.Duration
+
+	timeLayouts   []string
+	timeFormat    string
+	location      *time.Location
+	timestampUnit time.Duration
+
+	fieldConvert        FieldConvert
+	keyConvert          FieldConvert
+	tagNames            []string
+	fieldMatch          FieldMatch
+	disallowUnknownKeys bool
+	marshaler           bool
+}
+
+// Option 用于初始化 [Converter] 的选项
+type Option func(*Converter)
+
+var defaultConverter = New()
+
+// New 声明 [Converter] 对象
+func New(o ...Option) *Converter {
+	c := &Converter{
+		trueValues:  []string{"1", "t", "true", "on"},
+		falseValues: []string{"0", "f", "false", "off"},
+
+		floatFormat:     'f',
+		stringPrecision: -1,
+		bytesPrecision:  5,
+
+		truncation:   TruncateTowardZero,
+		durationUnit: time.Nanosecond,
+
+		timeLayouts: defaultTimeLayouts,
+		location:    time.UTC,
+
+		fieldConvert: defaultFieldConvert,
+		tagNames:     []string{"conv"},
+	}
+
+	for _, opt := range o {
+		opt(c)
+	}
+
+	return c
+}
+
+// 复制当前对象，并在副本上应用选项 o
+func (c *Converter) clone(o ...Option) *Converter {
+	cc := *c
+	for _, opt := range o {
+		opt(&cc)
+	}
+	return &cc
+}
+
+// WithStrict 严格模式
+//
+// 在严格模式下，任何会丢失信息的转换都将返回错误，而不是尽可能地返回一个近似值：
+//   - 浮点数转换成整数时，包含小数部分将返回 [ErrPrecision]，[WithTruncation] 不再有效；
+//   - 整数转换成浮点数或是 float64 转换成 float32 时，无法精确表示的值将返回 [ErrPrecision]；
+//   - 超出 float32 取值范围的值将返回 [ErrRange]；
+//   - 只有 0 和 1 两个数值可以转换成 bool，其它数值返回 [ErrRange]；
+//   - 字符串只有在 [WithBoolValues] 指定的列表中才能转换成 bool，数值字符串将返回 [ErrSyntax]。
+func WithStrict() Option {
+	return func(c *Converter) { c.strict = true }
+}
+
+// WithNilAsZero 将 nil 和空指针转换成目标类型的零值
+//
+// 默认情况下，各个转换函数会解引用指针，但是对于 nil 和空指针返回 [ErrNil]。
+// 指定此选项之后，nil 和空指针将被转换成目标类型的零值。
+func WithNilAsZero() Option {
+	return func(c *Converter) { c.nilAsZero = true }
+}
+
+// WithBoolValues 指定可以转换成 bool 的字符串
+//
+// 比较时不区分大小写，且会去掉首尾的空格。默认值为：
+//
+//	true: 1, t, true, on
+//	false: 0, f, false, off
+//
+// 此选项不影响数值字符串的转换，比如 "-123" 依然会被转换成 true。
+func WithBoolValues(trues, falses []string) Option {
+	return func(c *Converter) {
+		c.trueValues = trues
+		c.falseValues = falses
+	}
+}
+
+// WithFloatFormat 指定浮点数转换成字符串时的格式
+//
+// format 和 prec 的含义与 [strconv.FormatFloat] 相同，同时作用于 [Converter.String] 和 [Converter.Bytes]。
+// 默认情况下，format 为 'f'，String 的 prec 为 -1，Bytes 的 prec 为 5。
+func WithFloatFormat(format byte, prec int) Option {
+	return func(c *Converter) {
+		c.floatFormat = format
+		c.stringPrecision = prec
+		c.bytesPrecision = prec
+	}
+}
+
+// WithTruncation 指定浮点数转换成整数时对小数部分的处理方式
+func WithTruncation(t Truncation) Option {
+	return func(c *Converter) { c.truncation = t }
+}
+
+// WithDecimal 字符串转换成整数时只接受十进制的格式
+//
+// 默认情况下，字符串可以包含 0x、0o 和 0b 等进制前缀以及作为数字分隔符的下划线，
+// 指定此选项之后，这些格式都将返回 [ErrSyntax]。浮点数和科学计数法不受影响。
+func WithDecimal() Option {
+	return func(c *Converter) { c.decimal = true }
+}
+
+// WithNumberFormat 指定字符串转换成数值时采用的格式
+//
+// 作用于 [Converter.Float64] 和 [IntOf] 等函数对字符串和 []byte 的解析，
+// 解析之前会根据 f 去掉首尾的空白字符、货币符号以及千位分隔符，并将小数点替换为 .，比如：
+//
+//	c := New(WithNumberFormat(NumberFormat{Group: ".", Decimal: ",", Currency: []string{"€"}}))
+//	c.Float64("1.234,5 €") // 返回 1234.5
+//
+// 默认情况下，字符串会原样交由 [strconv] 解析。
+func WithNumberFormat(f NumberFormat) Option {
+	return func(c *Converter) {
+		f.Currency = append([]string(nil), f.Currency...)
+		c.numberFormat = &f
+	}
+}
+
+// WithDurationUnit 指定数值转换成 [time.Duration] 时采用的单位
+//
+// 比如指定为 [time.Second] 时，30 和 "30" 都将被转换成 30 秒，"1.5" 则为 1.5 秒。
+// 默认为 [time.Nanosecond]，unit 小于等于 0 时也采用默认值。
+func WithDurationUnit(unit time.Duration) Option {
+	return func(c *Converter) {
+		if unit <= 0 {
+			unit = time.Nanosecond
+		}
+		c.durationUnit = unit
+	}
+}
+
+// WithTimeLayouts 指定字符串转换成 [time.Time] 时可用的格式
+//
+// 按顺序尝试，采用第一个解析成功的格式。默认为 [time.RFC3339Nano]、2006-01-02T15:04:05、
+// 2006-01-02 15:04:05 和 2006-01-02。
+func WithTimeLayouts(layout ...string) Option {
+	return func(c *Converter) { c.timeLayouts = layout }
+}
+
+// WithTimeFormat 指定 [time.Time] 转换成字符串时采用的格式
+//
+// 作用于 [Converter.String] 和 [Converter.Bytes]，默认为空，表示采用 [time.Time.String] 的格式。
+func WithTimeFormat(layout string) Option {
+	return func(c *Converter) { c.timeFormat = layout }
+}
+
+// WithLocation 指定转换成 [time.Time] 时采用的时区
+//
+// 作用于不包含时区信息的字符串和 Unix 时间戳，默认为 [time.UTC]。
+func WithLocation(loc *time.Location) Option {
+	return func(c *Converter) {
+		if loc == nil {
+			loc = time.UTC
+		}
+		c.location = loc
+	}
+}
+
+// WithTimestampUnit 指定 Unix 时间戳的单位
+//
+// 可以是 [time.Second]、[time.Millisecond]、[time.Microsecond] 或 [time.Nanosecond]，
+// 其它值表示根据数值的大小自动判断，这也是默认的行为。
+func WithTimestampUnit(unit time.Duration) Option {
+	return func(c *Converter) {
+		switch unit {
+		case time.Second, time.Millisecond, time.Microsecond, time.Nanosecond:
+			c.timestampUnit = unit
+		default:
+			c.timestampUnit = 0
+		}
+	}
+}
+
+// WithFieldConvert 指定 [Converter.Obj2Map] 和 [Converter.Map2Obj] 默认的字段名转换函数
+//
+// 在 Obj2Map 中 conv 用于将字段名转换成键名，而在 Map2Obj 中则用于将键名转换成字段名。
+// 如果需要在两个方向上采用同一个函数，可以使用 [WithKeyConvert]。
+func WithFieldConvert(conv FieldConvert) Option {
+	return func(c *Converter) {
+		if conv == nil {
+			conv = defaultFieldConvert
+		}
+		c.fieldConvert = conv
+	}
+}
+
+// WithKeyConvert 指定字段名到键名的转换函数
+//
+// 与 [WithFieldConvert] 不同，无论是 [Converter.Obj2Map] 还是 [Converter.Map2Obj]，
+// conv 都只用于将字段名转换成键名，Map2Obj 通过比较转换后的字段名与 map 中的键名查找字段，
+// 所以同一个函数可以同时用于两个方向的转换，比如 [SnakeCase]。
+//
+// 指定此选项之后，[WithFieldConvert] 将不再有效，conv 为 nil 表示取消此选项。
+func WithKeyConvert(conv FieldConvert) Option {
+	return func(c *Converter) { c.keyConvert = conv }
+}
+
+// WithFieldMatch 指定 Map2Obj 中键名与字段名的匹配方式
+//
+// 无论采用哪种方式，精确匹配的字段总是优先。
+func WithFieldMatch(m FieldMatch) Option {
+	return func(c *Converter) { c.fieldMatch = m }
+}
+
+// WithDisallowUnknownKeys 在 Map2Obj 中遇到没有对应字段的键名时返回 [ErrUnknownKey]
+//
+// 默认情况下，这些键名会被忽略。
+func WithDisallowUnknownKeys() Option {
+	return func(c *Converter) { c.disallowUnknownKeys = true }
+}
+
+// WithMarshaler 在 Obj2Map 中采用接口生成字段的值
+//
+// 指定此选项之后，实现了 [encoding.TextMarshaler] 或 [fmt.Stringer] 的字段值
+// 将被转换成由接口生成的字符串，而不再遍历其字段，比如 [time.Time]。
+// 两者都实现时，优先采用 [encoding.TextMarshaler]。
+//
+// [Mapper] 接口不受此选项的影响。
+func WithMarshaler() Option {
+	return func(c *Converter) { c.marshaler = true }
+}
+
+// WithTagName 指定结构体字段的标签名称
+//
+// 多个名称时，按顺序查找，采用第一个存在的标签。默认值为 conv，
+// 如果需要同时读取 json 标签，可以使用 WithTagName("conv", "json")。
+func WithTagName(name ...string) Option {
+	return func(c *Converter) { c.tagNames = name }
+}
@@ -0,0 +1,425 @@
+// SPDX-FileCopyrightText: 2014-2026 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package conv
+
+import (
+	"errors"
+	"math"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestNew(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := New()
+	a.NotNil(c)
+
+	v1, err := c.Int8("12")
+	a.NotError(err).Equal(v1, int8(12))
+
+	v2, err := c.Bool("on")
+	a.NotError(err).True(v2)
+
+	v3, err := c.String(1.5)
+	a.NotError(err).Equal(v3, "1.5")
+
+	v4, err := c.Bytes(1.5)
+	a.NotError(err).Equal(v4, []byte("1.50000"))
+
+	v5, err := c.Slice([]int{1})
+	a.NotError(err).Equal(v5, []any{1})
+}
+
+func TestWithBoolValues(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := New(WithBoolValues([]string{"yes", "Y"}, []string{"no", "N"}))
+
+	v, err := c.Bool(" YES ")
+	a.NotError(err).True(v)
+	v, err = c.Bool("y")
+	a.NotError(err).True(v)
+	v, err = c.Bool([]byte("No"))
+	a.NotError(err).False(v)
+	v, err = c.Bool("-1")
+	a.NotError(err).True(v)
+
+	_, err = c.Bool("on")
+	a.ErrorIs(err, ErrSyntax)
+}
+
+func TestWithFloatFormat(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := New(WithFloatFormat('e', 2))
+
+	s, err := c.String(1234.5)
+	a.NotError(err).Equal(s, "1.23e+03")
+
+	b, err := c.Bytes(float32(1234.5))
+	a.NotError(err).Equal(b, []byte("1.23e+03"))
+}
+
+func TestWithTruncation(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := New(WithTruncation(TruncateRound))
+	v1, err := c.Int("1.5")
+	a.NotError(err).Equal(v1, 2)
+	v2, err := c.Uint(2.4)
+	a.NotError(err).Equal(v2, 2)
+
+	c = New(WithTruncation(TruncateFloor))
+	v1, err = c.Int(-1.5)
+	a.NotError(err).Equal(v1, -2)
+
+	c = New(WithTruncation(TruncateCeil))
+	v1, err = c.Int(1.1)
+	a.NotError(err).Equal(v1, 2)
+
+	c = New(WithTruncation(TruncateError))
+	v1, err = c.Int(2.0)
+	a.NotError(err).Equal(v1, 2)
+	_, err = c.Int("2.5")
+	a.ErrorIs(err, ErrPrecision)
+	_, err = c.Uint64(2.5)
+	a.ErrorIs(err, ErrPrecision)
+
+	var i8 int8
+	a.ErrorIs(c.Value(1.5, reflect.ValueOf(&i8)), ErrPrecision)
+}
+
+func TestWithDecimal(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := New(WithDecimal())
+	for _, str := range []string{"0x1F", "0o7", "0b1", "1_000"} {
+		_, err := c.Int(str)
+		a.ErrorIs(err, ErrSyntax, str)
+
+		_, err = c.Uint(str)
+		a.ErrorIs(err, ErrSyntax, str)
+	}
+
+	v, err := c.Int("1e3")
+	a.NotError(err).Equal(v, 1000)
+
+	v, err = c.Int("-010")
+	a.NotError(err).Equal(v, -10)
+}
+
+func TestWithNumberFormat(t *testing.T) {
+	a := assert.New(t, false)
+
+	en := New(WithNumberFormat(NumberFormat{Group: ",", Currency: []string{"$", "USD"}}))
+	for str, want := range map[string]float64{
+		"1,234.5":        1234.5,
+		" 1,234,567 ":    1234567,
+		"$1,234.5":       1234.5,
+		"-$1,234.5":      -1234.5,
+		"$-1,234.5":      -1234.5,
+		"- $ 12":         -12,
+		"1,234.5 USD":    1234.5,
+		"1234.5":         1234.5,
+		"+1,000":         1000,
+		"1,234.5e2":      123450,
+		"12,345,678.125": 12345678.125,
+	} {
+		v, err := en.Float64(str)
+		a.NotError(err, str).Equal(v, want, str)
+	}
+
+	v, err := en.Float64([]byte("$1,000"))
+	a.NotError(err).Equal(v, 1000)
+
+	_, err = en.Float64("€1")
+	a.ErrorIs(err, ErrSyntax)
+
+	de := New(WithNumberFormat(NumberFormat{Group: ".", Decimal: ",", Currency: []string{"€"}}))
+	for str, want := range map[string]float64{
+		"1.234,5":     1234.5,
+		"1.234,5 €":   1234.5,
+		"-1.234.567":  -1234567,
+		"€ 0,25":      0.25,
+		"1234,5":      1234.5,
+		" 1.000,00 ":  1000,
+		"-1.234,5 €":  -1234.5,
+		"1.234.567,8": 1234567.8,
+	} {
+		v, err := de.Float64(str)
+		a.NotError(err, str).Equal(v, want, str)
+	}
+
+	_, err = de.Float64("1,2,3")
+	a.ErrorIs(err, ErrSyntax)
+
+	fr := New(WithNumberFormat(NumberFormat{Group: "\u00a0", Decimal: ","}))
+	v, err = fr.Float64("1\u00a0234,5")
+	a.NotError(err).Equal(v, 1234.5)
+
+	// 整数
+	i, err := de.Int("1.234 €")
+	a.NotError(err).Equal(i, 1234)
+
+	i64, err := IntOfWith[int64](New(WithNumberFormat(NumberFormat{Group: ","})), []byte("-1,000,000"))
+	a.NotError(err).Equal(i64, -1000000)
+
+	u, err := en.Uint("$65,535")
+	a.NotError(err).Equal(u, 65535)
+
+	u8, err := UintOfWith[uint8](de, "1,5")
+	a.NotError(err).Equal(u8, uint8(1))
+
+	_, err = en.Uint8("1,000")
+	a.ErrorIs(err, ErrRange)
+
+	_, err = en.Uint("-$1")
+	a.Error(err)
+
+	// 默认不处理
+	_, err = New().Float64("1,234.5")
+	a.ErrorIs(err, ErrSyntax)
+}
+
+func TestWithFieldConvert(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := New(WithFieldConvert(strings.ToUpper))
+	m, err := c.Obj2Map(&C{SUB: &b1{}, PASSWORD: "p"})
+	a.NotError(err).Equal(m["PASSWORD"], "p")
+
+	obj := &C{}
+	a.NotError(c.Map2Obj(map[string]any{"password": "p"}, obj))
+	a.Equal(obj.PASSWORD, "p")
+}
+
+func TestWithKeyConvert(t *testing.T) {
+	a := assert.New(t, false)
+
+	type obj struct {
+		HTTPServerID int
+		UserName     string
+		Age          int `conv:"age_years"`
+	}
+
+	c := New(WithKeyConvert(SnakeCase))
+	m, err := c.Obj2Map(&obj{HTTPServerID: 1, UserName: "u", Age: 5})
+	a.NotError(err).Equal(m, map[string]any{"http_server_id": 1, "user_name": "u", "age_years": 5})
+
+	o := &obj{}
+	a.NotError(c.Map2Obj(m, o))
+	a.Equal(o, &obj{HTTPServerID: 1, UserName: "u", Age: 5})
+
+	// 字段名不再参与匹配
+	o = &obj{}
+	meta, err := c.Map2ObjWithMetadata(map[string]any{"UserName": "u"}, o)
+	a.NotError(err).Equal(meta.Unused, []string{"UserName"}).Empty(o.UserName)
+
+	// 与 WithFieldMatch 同时使用
+	c = New(WithKeyConvert(SnakeCase), WithFieldMatch(MatchCaseInsensitive))
+	o = &obj{}
+	a.NotError(c.Map2Obj(map[string]any{"USER_NAME": "u"}, o)).Equal(o.UserName, "u")
+
+	// WithFieldConvert 不再有效
+	c = New(WithKeyConvert(KebabCase), WithFieldConvert(strings.ToUpper))
+	o = &obj{}
+	a.NotError(c.Map2Obj(map[string]any{"user-name": "u", "USERNAME": "x"}, o)).Equal(o.UserName, "u")
+
+	// 嵌套对象
+	type outer struct {
+		InnerObj obj
+	}
+	out := &outer{}
+	c = New(WithKeyConvert(SnakeCase))
+	a.NotError(c.Map2Obj(map[string]any{"inner_obj": map[string]any{"user_name": "u"}}, out))
+	a.Equal(out.InnerObj.UserName, "u")
+}
+
+type level int
+
+func (l level) String() string { return "level-" + strconv.Itoa(int(l)) }
+
+var errText = errors.New("text")
+
+type badText struct{}
+
+func (badText) MarshalText() ([]byte, error) { return nil, errText }
+
+type ptrStringer struct{ N int }
+
+func (p *ptrStringer) String() string { return "ps" + strconv.Itoa(p.N) }
+
+func TestWithMarshaler_pointerReceiver(t *testing.T) {
+	a := assert.New(t, false)
+
+	type holder struct {
+		V ptrStringer
+		M map[string]ptrStringer
+	}
+	h := holder{V: ptrStringer{2}, M: map[string]ptrStringer{"a": {3}}}
+	want := map[string]any{"V": "ps2", "M": map[string]any{"a": "ps3"}}
+
+	c := New(WithMarshaler())
+	m, err := c.Obj2Map(h)
+	a.NotError(err).Equal(m, want)
+
+	m, err = c.Obj2Map(&h)
+	a.NotError(err).Equal(m, want)
+
+	// 未指定 WithMarshaler
+	m, err = New().Obj2Map(h)
+	a.NotError(err).Equal(m["V"], map[string]any{"N": 2})
+}
+
+func TestWithMarshaler(t *testing.T) {
+	a := assert.New(t, false)
+
+	type obj struct {
+		Created time.Time
+		Level   level
+		IP      net.IP
+		Times   []time.Time
+		Nil     *time.Time
+	}
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	o := &obj{Created: now, Level: 2, IP: net.IPv4(127, 0, 0, 1), Times: []time.Time{now}}
+
+	m, err := New(WithMarshaler()).Obj2Map(o)
+	a.NotError(err).Equal(m, map[string]any{
+		"Created": "2024-01-02T03:04:05Z",
+		"Level":   "level-2",
+		"IP":      "127.0.0.1",
+		"Times":   []any{"2024-01-02T03:04:05Z"},
+		"Nil":     nil,
+	})
+
+	// 默认不采用
+	m, err = New().Obj2Map(o)
+	a.NotError(err).
+		Equal(m["Created"], now).
+		Equal(m["Times"], []time.Time{now}).
+		Equal(m["Level"], level(2))
+
+	// MarshalText 返回的错误
+	_, err = New(WithMarshaler()).Obj2Map(&struct{ T badText }{})
+	a.ErrorIs(err, ErrCustom).ErrorIs(err, errText)
+	var e *Error
+	a.True(errors.As(err, &e)).Equal(e.Path, "T").Equal(e.Err, errText)
+}
+
+func TestGenericWith(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := New(WithStrict())
+	_, err := IntOfWith[int8](c, "1.5")
+	a.ErrorIs(err, ErrPrecision)
+	_, err = UintOfWith[uint16](c, 2.5)
+	a.ErrorIs(err, ErrPrecision)
+	_, err = SliceOfWith[int](c, []any{1, "2.5"})
+	a.ErrorIs(err, ErrPrecision)
+	_, err = MapOfWith[string, int](c, map[string]any{"a": 1.5})
+	a.ErrorIs(err, ErrPrecision)
+	_, err = ToWith[[]int](c, []float64{1, 1.5})
+	a.ErrorIs(err, ErrPrecision)
+
+	c = New(WithTruncation(TruncateRound), WithNumberFormat(NumberFormat{Group: ","}))
+	i8, err := IntOfWith[int8](c, "1.5")
+	a.NotError(err).Equal(i8, int8(2))
+	u, err := UintOfWith[uint](c, "1,000")
+	a.NotError(err).Equal(u, uint(1000))
+	s, err := SliceOfWith[int](c, []string{"1,000", "2.5"})
+	a.NotError(err).Equal(s, []int{1000, 3})
+	m, err := MapOfWith[string, int](c, map[string]string{"a": "1,000"})
+	a.NotError(err).Equal(m, map[string]int{"a": 1000})
+	v, err := ToWith[*int](c, "2,000.5")
+	a.NotError(err).Equal(*v, 2001)
+}
+
+func TestWithTagName(t *testing.T) {
+	a := assert.New(t, false)
+
+	type obj struct {
+		ID   int `yaml:"id" json:"json_id"`
+		Name string
+	}
+
+	c := New(WithTagName("yaml"))
+	m, err := c.Obj2Map(&obj{ID: 1, Name: "n"})
+	a.NotError(err).Equal(m, map[string]any{"id": 1, "Name": "n"})
+
+	o := &obj{}
+	a.NotError(c.Map2Obj(map[string]any{"id": 2}, o)).Equal(o.ID, 2)
+
+	// 未指定的 json 标签无效
+	m, err = c.Obj2Map(&obj{ID: 1})
+	a.NotError(err).NotContains(m, "json_id")
+}
+
+func TestWithStrict(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := New(WithStrict(), WithTruncation(TruncateRound))
+
+	// 整数
+	v1, err := c.Int("3.0")
+	a.NotError(err).Equal(v1, 3)
+	_, err = c.Int("3.9")
+	a.ErrorIs(err, ErrPrecision)
+	_, err = c.Int64(3.9)
+	a.ErrorIs(err, ErrPrecision)
+	_, err = c.Uint8(float32(1.5))
+	a.ErrorIs(err, ErrPrecision)
+
+	// 浮点数
+	v2, err := c.Float64(int64(1 << 53))
+	a.NotError(err).Equal(v2, float64(1<<53))
+	_, err = c.Float64(int64(1<<53 + 1))
+	a.ErrorIs(err, ErrPrecision)
+	_, err = c.Float64(uint64(math.MaxUint64))
+	a.ErrorIs(err, ErrPrecision)
+	v3, err := c.Float32(0.5)
+	a.NotError(err).Equal(v3, float32(0.5))
+	v3, err = c.Float32("0.1")
+	a.NotError(err).Equal(v3, float32(0.1))
+	_, err = c.Float32(0.1)
+	a.ErrorIs(err, ErrPrecision)
+	_, err = c.Float32(1e300)
+	a.ErrorIs(err, ErrRange)
+	_, err = c.Float32("1e300")
+	a.ErrorIs(err, ErrRange)
+	v3, err = c.Float32(math.Inf(1))
+	a.NotError(err).True(math.IsInf(float64(v3), 1))
+
+	var f32 float32
+	a.ErrorIs(c.Value(1e300, reflect.ValueOf(&f32)), ErrRange)
+
+	// bool
+	v4, err := c.Bool(1)
+	a.NotError(err).True(v4)
+	v4, err = c.Bool(0.0)
+	a.NotError(err).False(v4)
+	v4, err = c.Bool("true")
+	a.NotError(err).True(v4)
+	_, err = c.Bool(5)
+	a.ErrorIs(err, ErrRange)
+	_, err = c.Bool("0.0001")
+	a.ErrorIs(err, ErrSyntax)
+
+	// 非严格模式
+	v1, err = Int("3.9")
+	a.NotError(err).Equal(v1, 3)
+	v4, err = Bool("0.0001")
+	a.NotError(err).True(v4)
+	v3, err = Float32(1e300)
+	a.NotError(err).True(math.IsInf(float64(v3), 1))
+}
@@ -11,4 +11,14 @@
 //	conv.Int("123", 0)      // 返回 123 数值和 nil 的 error 接口
 //	v := 5
 //	conv.Value("3", reflect.ValueOf(v)) // 将 3 转换成数值,并写入 v 中。
+//
+// 包级别的函数均采用默认的转换规则，如果需要改变转换规则，
+// 可以通过 [New] 声明一个 [Converter] 实例，并调用其同名的方法：
+//
+//	c := conv.New(conv.WithTruncation(conv.TruncateRound))
+//	c.Int("1.5") // 返回 2
+//
+// 泛型函数无法作为方法，[IntOf] 等泛型函数由 [IntOfWith] 等以 *With 结尾的函数代替：
+//
+//	conv.IntOfWith[int8](c, "1.5") // 返回 2
 package conv
@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2014-2026 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package conv
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const durationTarget = "time.Duration"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Duration 将 val 转换成 [time.Duration] 类型或是在无法转换的情况下返回 error
+//
+// 字符串除了支持 [time.ParseDuration] 的格式之外，还支持 d（天）和 w（周）两个单位，比如 2d12h；
+// 数值以及数值格式的字符串表示以 [WithDurationUnit] 指定单位的时长，默认为纳秒，
+// 其中数值格式的字符串与 [Float64] 相同，可以由 [WithNumberFormat] 指定其格式。
+func Duration(val any) (time.Duration, error) { return defaultConverter.Duration(val) }
+
+// Duration 将 val 转换成 [time.Duration] 类型或是在无法转换的情况下返回 error
+func (c *Converter) Duration(val any) (time.Duration, error) {
+	switch ret := val.(type) {
+	case time.Duration:
+		return ret, nil
+	case int64:
+		return c.int2Duration(ret, ret)
+	case uint64:
+		if ret > math.MaxInt64 {
+			return 0, rangeError(ret, durationTarget)
+		}
+		return c.int2Duration(ret, int64(ret))
+	case float32:
+		return c.float2Duration(ret, float64(ret))
+	case float64:
+		return c.float2Duration(ret, ret)
+	case []byte:
+		return c.str2Duration(ret, string(ret))
+	case string:
+		return c.str2Duration(ret, ret)
+	case bool:
+		return 0, typeError(ret, durationTarget)
+	default:
+		if v, ok := indirect(val); ok {
+			if v == nil {
+				return 0, c.nilError(val, durationTarget)
+			}
+			return c.Duration(v)
+		}
+
+		if u, ok := underlying(val); ok {
+			ret, err := c.Duration(u)
+			return ret, replaceErrorValue(err, val)
+		}
+		return 0, typeError(val, durationTarget)
+	}
+}
+
+// MustDuration 将 val 转换成 [time.Duration] 类型或是在无法转换的情况下返回 def 参数
+func MustDuration(val any, def ...time.Duration) time.Duration {
+	if ret, err := Duration(val); err == nil {
+		return ret
+	}
+	return def[0]
+}
+
+// 将以 c.durationUnit 为单位的整数 n 转换成 time.Duration
+func (c *Converter) int2Duration(val any, n int64) (time.Duration, error) {
+	unit := int64(c.durationUnit)
+	if n > math.MaxInt64/unit || n < math.MinInt64/unit {
+		return 0, rangeError(val, durationTarget)
+	}
+	return time.Duration(n * unit), nil
+}
+
+// 将以 c.durationUnit 为单位的浮点数 f 转换成 time.Duration，不足一纳秒的部分由 c.truncation 处理。
+func (c *Converter) float2Duration(val any, f float64) (time.Duration, error) {
+	n, err := c.float2Int64(val, f*float64(c.durationUnit))
+	if e, ok := err.(*Error); ok {
+		e.Target = durationTarget
+	}
+	return time.Duration(n), err
+}
+
+func (c *Converter) str2Duration(val any, str string) (time.Duration, error) {
+	str = strings.TrimSpace(normalizeDigits(str))
+	if str == "" {
+		return 0, syntaxError(val, durationTarget, nil)
+	}
+
+	// 不带单位的数值，由 c.numberFormat 处理之后再解析。
+	// 带单位的字符串采用固定的格式，不受 c.numberFormat 的影响。
+	if num := c.normalizeNumber(str); num != "" && isDurationNumber(num[len(num)-1]) {
+		s, base := c.intBase(num)
+		if base == 10 && strings.ContainsAny(s, ".eE") {
+			f, err := strconv.ParseFloat(s, 64)
+			if errors.Is(err, strconv.ErrRange) {
+				return 0, newError(val, durationTarget, ErrRange, err)
+			} else if err != nil {
+				return 0, syntaxError(val, durationTarget, err)
+			}
+			return c.float2Duration(val, f)
+		}
+
+		n, err := c.parseInt64(val, num)
+		if e, ok := err.(*Error); ok {
+			e.Target = durationTarget
+			return 0, e
+		}
+		return c.int2Duration(val, n)
+	}
+
+	return parseDuration(val, str)
+}
+
+func isDurationNumber(b byte) bool { return (b >= '0' && b <= '9') || b == '.' }
+
+// 在 [time.ParseDuration] 的基础上添加了对 d 和 w 单位的支持
+func parseDuration(val any, str string) (time.Duration, error) {
+	s := str
+	neg := false
+	if s[0] == '-' || s[0] == '+' {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, syntaxError(val, durationTarget, nil)
+	}
+
+	isNum := func(r rune) bool { return (r >= '0' && r <= '9') || r == '.' }
+
+	var d time.Duration
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool { return !isNum(r) })
+		if i <= 0 { // 缺少数值或是单位
+			return 0, syntaxError(val, durationTarget, nil)
+		}
+		j := strings.IndexFunc(s[i:], isNum)
+		if j < 0 {
+			j = len(s) - i
+		}
+		num, unit := s[:i], s[i:i+j]
+		s = s[i+j:]
+
+		var v time.Duration
+		switch unit {
+		case "d", "w":
+			f, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return 0, syntaxError(val, durationTarget, err)
+			}
+
+			f *= float64(24 * time.Hour)
+			if unit == "w" {
+				f *= 7
+			}
+			if f >= math.MaxInt64 {
+				return 0, rangeError(val, durationTarget)
+			}
+			v = time.Duration(f)
+		default:
+			var err error
+			if v, err = time.ParseDuration(num + unit); err != nil {
+				return 0, syntaxError(val, durationTarget, err)
+			}
+		}
+
+		if d > math.MaxInt64-v {
+			return 0, rangeError(val, durationTarget)
+		}
+		d += v
+	}
+
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
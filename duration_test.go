@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2014-2026 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package conv
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestDuration(t *testing.T) {
+	a := assert.New(t, false)
+
+	type myDuration int64
+	i := 5
+
+	for val, want := range map[any]time.Duration{
+		time.Second:       time.Second,
+		30:                30,
+		int8(3):           3,
+		uint64(4):         4,
+		2.9:               2,
+		myDuration(6):     6,
+		&i:                5,
+		"30":              30,
+		" 1_000 ":         1000,
+		"1e3":             1000,
+		"1h30m":           90 * time.Minute,
+		"-1.5h":           -90 * time.Minute,
+		"300ms":           300 * time.Millisecond,
+		"2µs":             2 * time.Microsecond,
+		"2d":              48 * time.Hour,
+		"1.5d12h":         48 * time.Hour,
+		"1w1d":            8 * 24 * time.Hour,
+		"+1w":             7 * 24 * time.Hour,
+		string("0"):       0,
+		"9223372036854ms": 9223372036854 * time.Millisecond,
+	} {
+		d, err := Duration(val)
+		a.NotError(err, val).Equal(d, want, val)
+	}
+
+	d, err := Duration([]byte("2m"))
+	a.NotError(err).Equal(d, 2*time.Minute)
+
+	for _, val := range []any{"", "-", "h", "1x", "1h-", "1.2.3d", "d1"} {
+		_, err = Duration(val)
+		a.ErrorIs(err, ErrSyntax, val)
+	}
+
+	for _, val := range []any{uint64(math.MaxUint64), "1000000000000w", "106751d24h", math.Inf(1), "1e400", "-1e400"} {
+		_, err = Duration(val)
+		a.ErrorIs(err, ErrRange, val)
+	}
+
+	_, err = Duration(true)
+	a.ErrorIs(err, ErrUnsupported)
+
+	_, err = Duration([]int{1})
+	a.ErrorIs(err, ErrUnsupported)
+
+	_, err = Duration(nil)
+	a.ErrorIs(err, ErrNil)
+
+	a.Equal(MustDuration("x", time.Second), time.Second).
+		Equal(MustDuration("2s", time.Second), 2*time.Second)
+}
+
+func TestWithDurationUnit(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := New(WithDurationUnit(time.Second))
+	for val, want := range map[any]time.Duration{
+		30:        30 * time.Second,
+		"30":      30 * time.Second,
+		"1.5":     1500 * time.Millisecond,
+		0.25:      250 * time.Millisecond,
+		"2m":      2 * time.Minute,
+		"2d":      48 * time.Hour,
+		time.Hour: time.Hour,
+	} {
+		d, err := c.Duration(val)
+		a.NotError(err, val).Equal(d, want, val)
+	}
+
+	_, err := c.Duration(int64(math.MaxInt64))
+	a.ErrorIs(err, ErrRange)
+
+	_, err = c.Duration("-9223372036854775807")
+	a.ErrorIs(err, ErrRange)
+
+	// 严格模式下，不足一纳秒的部分返回错误。
+	c = New(WithStrict())
+	_, err = c.Duration(1.5)
+	a.ErrorIs(err, ErrPrecision)
+
+	// 数值字符串与 Float64 采用相同的 NumberFormat
+	c = New(WithDurationUnit(time.Second), WithNumberFormat(NumberFormat{Group: ".", Decimal: ","}))
+	for val, want := range map[string]time.Duration{
+		"1,5":   1500 * time.Millisecond,
+		"1.500": 1500 * time.Second,
+		"0,25":  250 * time.Millisecond,
+		"1.5h":  90 * time.Minute, // 带单位的字符串不受影响
+		"2d":    48 * time.Hour,
+	} {
+		d, err := c.Duration(val)
+		a.NotError(err, val).Equal(d, want, val)
+
+		if f, err := c.Float64(val); err == nil {
+			a.Equal(d, time.Duration(f*float64(time.Second)), val)
+		}
+	}
+
+	c = New(WithDurationUnit(0))
+	d, err := c.Duration(3)
+	a.NotError(err).Equal(d, 3)
+}
+
+func TestValue_duration(t *testing.T) {
+	a := assert.New(t, false)
+
+	var d time.Duration
+	a.NotError(Value("1h", reflect.ValueOf(&d))).Equal(d, time.Hour)
+
+	var p *time.Duration
+	a.NotError(Value("2d", reflect.ValueOf(&p))).Equal(*p, 48*time.Hour)
+
+	a.ErrorIs(Value("x", reflect.ValueOf(&d)), ErrSyntax)
+
+	type config struct {
+		Timeout  time.Duration
+		Interval *time.Duration
+		Retries  []time.Duration
+	}
+	c := New(WithDurationUnit(time.Second))
+	cfg := &config{}
+	a.NotError(c.Map2Obj(map[string]any{
+		"Timeout":  30,
+		"Interval": "1m",
+		"Retries":  []any{"1", 2, "3s"},
+	}, cfg))
+	a.Equal(cfg.Timeout, 30*time.Second).
+		Equal(*cfg.Interval, time.Minute).
+		Equal(cfg.Retries, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second})
+
+	err := Map2Obj(map[string]any{"Timeout": "abc"}, cfg, nil)
+	a.ErrorIs(err, ErrSyntax)
+	var e *Error
+	a.True(errors.As(err, &e)).Equal(e.Path, "Timeout").Equal(e.Target, "time.Duration")
+}
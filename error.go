@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2014-2026 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package conv
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// 错误的分类
+//
+// 可以通过 [errors.Is] 判断 [Error.Kind] 的值。
+var (
+	ErrSyntax      = errors.New("格式错误")
+	ErrRange       = errors.New("超出取值范围")
+	ErrUnsupported = errors.New("不支持的类型")
+	ErrNegative    = errors.New("负数无法转换成无符号整数")
+	ErrLength      = errors.New("长度不一致")
+	ErrPrecision   = errors.New("丢失精度")
+	ErrNil         = errors.New("空值")
+	ErrUnknownKey  = errors.New("未知的键名")
+	ErrRequired    = errors.New("缺少必要的值")
+	ErrCustom      = errors.New("自定义的转换失败") // 由用户提供的转换函数或是接口返回的错误，原始错误保存在 [Error.Err]。
+)
+
+// Error 转换失败时返回的错误类型
+type Error struct {
+	Path   string // 出错的字段路径，比如 servers[3].Port，顶层的值为空。
+	Value  any    // 需要转换的值
+	Source string // Value 的类型名称
+	Target string // 目标类型的名称
+	Kind   error  // 错误的分类，为 ErrSyntax、ErrRange 等值之一
+	Err    error  // 底层的错误信息，比如 *strconv.NumError，可能为空。
+}
+
+func newError(val any, t string, kind, err error) *Error {
+	return &Error{
+		Value:  val,
+		Source: fmt.Sprintf("%T", val),
+		Target: t,
+		Kind:   kind,
+		Err:    err,
+	}
+}
+
+// 抛出一个类型无法转换的错误
+// val 当前值；t 目标类型。
+func typeError(val any, t string) error { return newError(val, t, ErrUnsupported, nil) }
+
+// 抛出一个格式错误，err 为底层的错误信息，可以为空。
+func syntaxError(val any, t string, err error) error { return newError(val, t, ErrSyntax, err) }
+
+// 抛出一个超出取值范围的错误
+func rangeError(val any, t string) error { return newError(val, t, ErrRange, nil) }
+
+// 包装由用户提供的转换函数返回的错误 err
+func customError(val any, t string, err error) error { return newError(val, t, ErrCustom, err) }
+
+// 抛出一个负数无法转换成无符号整数的错误
+func negativeError(val any, t string) error { return newError(val, t, ErrNegative, nil) }
+
+// 为 err 的路径添加上一级的字段名 name
+//
+// 仅对 [Error] 类型的错误有效，其它类型的错误原样返回。
+func withPath(err error, name string) error {
+	var e *Error
+	if errors.As(err, &e) {
+		switch {
+		case e.Path == "":
+			e.Path = name
+		case e.Path[0] == '[':
+			e.Path = name + e.Path
+		default:
+			e.Path = name + "." + e.Path
+		}
+	}
+	return err
+}
+
+// 为 err 的路径添加上一级的索引 index
+func withIndex(err error, index int) error {
+	return withPath(err, "["+strconv.Itoa(index)+"]")
+}
+
+func (e *Error) Error() string {
+	msg := "conv: "
+	if e.Path != "" {
+		msg += e.Path + ": "
+	}
+
+	msg += fmt.Sprintf("%s:%v 无法转换成 %s 类型", e.Source, e.Value, e.Target)
+	switch {
+	case e.Kind == ErrUnsupported: // 类型不支持的信息已经包含在 msg 中
+	case e.Kind == ErrCustom && e.Err != nil: // 用户的错误信息比分类更有意义
+		msg += ": " + e.Err.Error()
+	case e.Kind != nil:
+		msg += ": " + e.Kind.Error()
+	case e.Err != nil: // 由用户自行构建的对象，可能未指定 Kind。
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Is 判断 target 是否与 [Error.Kind] 相同
+func (e *Error) Is(target error) bool { return e.Kind == target }
+
+// Unwrap 返回底层的错误信息
+func (e *Error) Unwrap() error { return e.Err }
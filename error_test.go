@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2014-2026 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package conv
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestError(t *testing.T) {
+	a := assert.New(t, false)
+
+	_, err := Int("1a")
+	a.ErrorIs(err, ErrSyntax).
+		ErrorIs(err, strconv.ErrSyntax).
+		ErrorString(err, "conv: string:1a 无法转换成 int64 类型: 格式错误")
+	var ce *Error
+	a.True(errors.As(err, &ce)).
+		Equal(ce.Value, "1a").
+		Equal(ce.Source, "string").
+		Equal(ce.Target, "int64").
+		Equal(ce.Kind, ErrSyntax)
+	var ne *strconv.NumError
+	a.True(errors.As(err, &ne)).Equal(ne.Func, "ParseInt")
+
+	_, err = Int8(int64(300))
+	a.ErrorIs(err, ErrRange).False(errors.Is(err, ErrSyntax))
+	a.True(errors.As(err, &ce)).
+		Equal(ce.Value, int64(300)).
+		Equal(ce.Source, "int64").
+		Equal(ce.Target, "int8")
+
+	_, err = Uint(-5)
+	a.ErrorIs(err, ErrNegative)
+
+	// 字符串与数值的错误类型相同
+	for _, val := range []any{"-1", []byte("-1"), "-0.5", "-0x10", "-99999999999999999999"} {
+		_, err = Uint(val)
+		a.ErrorIs(err, ErrNegative, val)
+	}
+	_, err = Uint("-x")
+	a.ErrorIs(err, ErrSyntax)
+	u, err := Uint("-0")
+	a.NotError(err).Equal(u, 0)
+
+	_, err = Float64("1e400")
+	a.ErrorIs(err, ErrRange).ErrorIs(err, strconv.ErrRange)
+
+	// 保留 strconv.NumError
+	_, err = Int64("99999999999999999999")
+	a.ErrorIs(err, ErrRange).ErrorIs(err, strconv.ErrRange)
+	a.True(errors.As(err, &ne)).Equal(ne.Func, "ParseInt")
+
+	_, err = Uint64("99999999999999999999")
+	a.ErrorIs(err, ErrRange).ErrorIs(err, strconv.ErrRange)
+	a.True(errors.As(err, &ne)).Equal(ne.Func, "ParseUint")
+
+	_, err = Bool("abc")
+	a.ErrorIs(err, ErrSyntax)
+
+	_, err = String([]int{1})
+	a.ErrorIs(err, ErrUnsupported).
+		ErrorString(err, "conv: []int:[1] 无法转换成 string 类型")
+
+	t1 := [2]int{}
+	err = Value([]int{1, 2, 3}, reflect.ValueOf(&t1))
+	a.ErrorIs(err, ErrLength)
+	a.True(errors.As(err, &ce)).Equal(ce.Target, "[2]int")
+}
+
+func TestError_zero(t *testing.T) {
+	a := assert.New(t, false)
+
+	e := &Error{}
+	a.NotPanic(func() { _ = e.Error() }).
+		Equal(e.Error(), "conv: :<nil> 无法转换成  类型").
+		False(errors.Is(e, ErrSyntax))
+
+	e = &Error{Source: "string", Value: "x", Target: "int", Err: errors.New("err")}
+	a.Equal(e.Error(), "conv: string:x 无法转换成 int 类型: err")
+}
+
+func TestError_Path(t *testing.T) {
+	a := assert.New(t, false)
+
+	_, err := SliceOf[int]([]any{1, 2, "x"})
+	a.ErrorString(err, "conv: [2]: string:x 无法转换成 int64 类型: 格式错误")
+	var ce *Error
+	a.True(errors.As(err, &ce)).Equal(ce.Path, "[2]")
+
+	t1 := [][]int{}
+	err = Value([][]string{{"1"}, {"2", "x"}}, reflect.ValueOf(&t1))
+	a.True(errors.As(err, &ce)).Equal(ce.Path, "[1][1]")
+
+	t2 := [2][]uint8{}
+	err = Value([][]int{{1}, {2, 300}}, reflect.ValueOf(&t2))
+	a.ErrorIs(err, ErrRange).True(errors.As(err, &ce)).Equal(ce.Path, "[1][1]")
+
+	a.Equal(withPath(rangeError(1, "int8"), "Port").(*Error).Path, "Port")
+	a.Equal(withPath(withPath(rangeError(1, "int8"), "Port"), "servers").(*Error).Path, "servers.Port")
+	a.Equal(withIndex(withPath(rangeError(1, "int8"), "Port"), 3).(*Error).Path, "[3].Port")
+	a.Equal(withPath(withIndex(withPath(rangeError(1, "int8"), "Port"), 3), "servers").(*Error).Path, "servers[3].Port")
+
+	err = errors.New("other")
+	a.Equal(withPath(err, "servers"), err)
+}
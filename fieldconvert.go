@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2014-2026 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package conv
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SnakeCase 转换成小写的下划线格式
+//
+// 连续的大写字母会被当作一个单词，比如 HTTPServerID 转换成 http_server_id。
+func SnakeCase(src string) string { return joinWords(splitWords(src), "_", strings.ToLower) }
+
+// ScreamingSnakeCase 转换成大写的下划线格式
+//
+// 比如 HTTPServerID 转换成 HTTP_SERVER_ID。
+func ScreamingSnakeCase(src string) string {
+	return joinWords(splitWords(src), "_", strings.ToUpper)
+}
+
+// KebabCase 转换成小写的中横线格式
+//
+// 比如 HTTPServerID 转换成 http-server-id。
+func KebabCase(src string) string { return joinWords(splitWords(src), "-", strings.ToLower) }
+
+// CamelCase 转换成首字母小写的驼峰格式
+//
+// 比如 HTTPServerID 和 http_server_id 都会转换成 httpServerId。
+func CamelCase(src string) string {
+	words := splitWords(src)
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.ToLower(words[0]) + joinWords(words[1:], "", title)
+}
+
+// PascalCase 转换成首字母大写的驼峰格式
+//
+// 比如 http_server_id 转换成 HttpServerId。
+func PascalCase(src string) string { return joinWords(splitWords(src), "", title) }
+
+// LowerFirst 将开头的大写字母转换成小写
+//
+// 开头连续的大写字母会被当作一个单词，比如 HTTPServer 转换成 httpServer，ID 转换成 id。
+func LowerFirst(src string) string {
+	runes := []rune(src)
+	for i, r := range runes {
+		if !unicode.IsUpper(r) {
+			break
+		}
+
+		// 大写字母之后紧跟着小写字母，说明是下一个单词的开头。
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(r)
+	}
+	return string(runes)
+}
+
+// UpperFirst 将首字母转换成大写
+func UpperFirst(src string) string {
+	r, size := utf8.DecodeRuneInString(src)
+	if size == 0 {
+		return src
+	}
+	return string(unicode.ToUpper(r)) + src[size:]
+}
+
+// ComposeFieldConvert 将多个 [FieldConvert] 组合成一个
+//
+// 按参数顺序依次调用，前一个函数的返回值作为后一个函数的参数。
+func ComposeFieldConvert(conv ...FieldConvert) FieldConvert {
+	return func(src string) string {
+		for _, c := range conv {
+			src = c(src)
+		}
+		return src
+	}
+}
+
+func title(word string) string {
+	return UpperFirst(strings.ToLower(word))
+}
+
+func joinWords(words []string, sep string, f func(string) string) string {
+	for i, w := range words {
+		words[i] = f(w)
+	}
+	return strings.Join(words, sep)
+}
+
+func isWordSeparator(r rune) bool {
+	return r == '_' || r == '-' || r == '.' || unicode.IsSpace(r)
+}
+
+// 将 src 拆分成单词
+//
+// 下划线、中横线、点和空白字符都被当作分隔符，此外以下位置也会被拆分：
+//   - 非大写字母之后的大写字母，比如 serverID 拆分成 server 和 ID；
+//   - 连续大写字母之后紧跟着小写字母，比如 HTTPServer 拆分成 HTTP 和 Server。
+func splitWords(src string) []string {
+	runes := []rune(src)
+	words := make([]string, 0, 4)
+	start := -1
+
+	for i, r := range runes {
+		if isWordSeparator(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			if !unicode.IsUpper(prev) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+
+		if start < 0 {
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2014-2026 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package conv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestSplitWords(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(splitWords(""), []string{})
+	a.Equal(splitWords("ID"), []string{"ID"})
+	a.Equal(splitWords("Name"), []string{"Name"})
+	a.Equal(splitWords("HTTPServerID"), []string{"HTTP", "Server", "ID"})
+	a.Equal(splitWords("userID2Name"), []string{"user", "ID2", "Name"})
+	a.Equal(splitWords("http_server-id"), []string{"http", "server", "id"})
+	a.Equal(splitWords("__a  B.c__"), []string{"a", "B", "c"})
+	a.Equal(splitWords("版本Version"), []string{"版本", "Version"})
+}
+
+func TestFieldConvert(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := []struct {
+		src, snake, screaming, kebab, camel, pascal, lower string
+	}{
+		{"", "", "", "", "", "", ""},
+		{"ID", "id", "ID", "id", "id", "Id", "id"},
+		{"Name", "name", "NAME", "name", "name", "Name", "name"},
+		{"HTTPServerID", "http_server_id", "HTTP_SERVER_ID", "http-server-id", "httpServerId", "HttpServerId", "httpServerID"},
+		{"http_server_id", "http_server_id", "HTTP_SERVER_ID", "http-server-id", "httpServerId", "HttpServerId", "http_server_id"},
+		{"userID", "user_id", "USER_ID", "user-id", "userId", "UserId", "userID"},
+		{"Port8080", "port8080", "PORT8080", "port8080", "port8080", "Port8080", "port8080"},
+	}
+
+	for _, item := range data {
+		a.Equal(SnakeCase(item.src), item.snake, item.src).
+			Equal(ScreamingSnakeCase(item.src), item.screaming, item.src).
+			Equal(KebabCase(item.src), item.kebab, item.src).
+			Equal(CamelCase(item.src), item.camel, item.src).
+			Equal(PascalCase(item.src), item.pascal, item.src).
+			Equal(LowerFirst(item.src), item.lower, item.src)
+	}
+
+	a.Equal(UpperFirst(""), "")
+	a.Equal(UpperFirst("name"), "Name")
+	a.Equal(UpperFirst("ébc"), "Ébc")
+}
+
+func TestComposeFieldConvert(t *testing.T) {
+	a := assert.New(t, false)
+
+	conv := ComposeFieldConvert(SnakeCase, strings.ToUpper)
+	a.Equal(conv("HTTPServer"), "HTTP_SERVER")
+	a.Equal(ComposeFieldConvert()("Name"), "Name")
+
+	type obj struct {
+		HTTPServer string
+		UserID     int
+	}
+	m, err := Obj2Map(&obj{HTTPServer: "s", UserID: 1}, SnakeCase)
+	a.NotError(err).Equal(m, map[string]any{"http_server": "s", "user_id": 1})
+
+	o := &obj{}
+	a.NotError(Map2Obj(map[string]any{"HTTPServer": "s"}, o, UpperFirst))
+	a.Equal(o.HTTPServer, "s")
+}
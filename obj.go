@@ -5,9 +5,11 @@
 package conv
 
 import (
-	"errors"
+	"encoding"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 // FieldConvert 字段转换
@@ -18,8 +20,106 @@ type FieldConvert func(src string) (dest string)
 // FieldConvert 的默认实现
 func defaultFieldConvert(src string) string { return src }
 
+// Mapper 自定义 Obj2Map 转换结果的接口
+//
+// 实现了该接口的对象在 Obj2Map 中由 ToMap 生成对应的 map，而不再遍历其字段。
+type Mapper interface {
+	ToMap() (map[string]any, error)
+}
+
+// FieldMatch Map2Obj 中键名与字段名的匹配方式
+type FieldMatch int8
+
+const (
+	MatchExact           FieldMatch = iota // 精确匹配，默认值。
+	MatchCaseInsensitive                   // 不区分大小写
+	MatchSnakeCase                         // 不区分大小写且忽略下划线，比如 http_server 可以匹配 HTTPServer。
+	MatchKebabCase                         // 不区分大小写且忽略中横线，比如 http-server 可以匹配 HTTPServer。
+)
+
+func (m FieldMatch) normalize(s string) string {
+	switch m {
+	case MatchCaseInsensitive:
+		return strings.ToLower(s)
+	case MatchSnakeCase:
+		return strings.ToLower(strings.ReplaceAll(s, "_", ""))
+	case MatchKebabCase:
+		return strings.ToLower(strings.ReplaceAll(s, "-", ""))
+	default:
+		return s
+	}
+}
+
+// 字段标签的内容
+type fieldTag struct {
+	name      string // 标签中指定的名称，为空表示未指定。
+	omitempty bool
+	required  bool
+	ignore    bool // 是否忽略该字段
+}
+
+// 解析字段的标签
+//
+// 标签的格式为 `conv:"name,omitempty,required"`，name 为 - 时表示忽略该字段，
+// 标签名称由 c.tagNames 指定，采用第一个存在的标签。
+func (c *Converter) parseTag(field reflect.StructField) (tag fieldTag) {
+	var str string
+	for _, n := range c.tagNames {
+		if t, found := field.Tag.Lookup(n); found {
+			str = t
+			break
+		}
+	}
+
+	name, opts, hasOpts := strings.Cut(str, ",")
+	if name == "-" && !hasOpts { // 与 json 相同，- 表示忽略，-, 表示名称为 -。
+		tag.ignore = true
+		return tag
+	}
+
+	tag.name = name
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		switch opt {
+		case "omitempty":
+			tag.omitempty = true
+		case "required":
+			tag.required = true
+		}
+	}
+	return tag
+}
+
+// 判断 v 是否为 omitempty 意义上的空值，与 encoding/json 的判断方式相同。
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	default:
+		return false
+	}
+}
+
 // 将 obj 对象转换成 map[string]any 格式的数据
-func obj2Map(obj any, maps map[string]any, conv FieldConvert) error {
+func (c *Converter) obj2Map(obj any, maps map[string]any, conv FieldConvert) error {
+	if m, ok := asMapper(obj); ok {
+		mm, err := m.ToMap()
+		if err != nil {
+			return customError(obj, "map[string]any", err)
+		}
+		for k, v := range mm {
+			maps[k] = v
+		}
+		return nil
+	}
+
 	objVal := reflect.ValueOf(obj)
 	for objVal.Kind() == reflect.Pointer { // 如果是指针，则获取指向的对象
 		objVal = objVal.Elem()
@@ -38,30 +138,222 @@ func obj2Map(obj any, maps map[string]any, conv FieldConvert) error {
 			continue
 		}
 
-		var err error
-		switch {
-		case fieldType.Anonymous: // 匿名字段
-			err = obj2Map(fieldVal.Interface(), maps, conv)
-		case fieldType.Type.Kind() == reflect.Pointer: // 如果是指针，就获取指针指向的元素
-			fieldVal = fieldVal.Elem()
-			fallthrough
-		case fieldType.Type.Kind() == reflect.Struct: // 嵌套类型
-			m := make(map[string]any)
-			err = obj2Map(fieldVal.Interface(), m, conv)
-			maps[conv(fieldType.Name)] = m
-		default:
-			maps[conv(fieldType.Name)] = fieldVal.Interface()
+		tag := c.parseTag(fieldType)
+		if tag.ignore || (tag.omitempty && isEmptyValue(fieldVal)) {
+			continue
 		}
+		name := tag.name
+
+		if fieldType.Anonymous && name == "" { // 未指定名称的匿名字段
+			if fieldVal.Kind() == reflect.Pointer && fieldVal.IsNil() {
+				continue
+			}
+
+			if err := c.obj2Map(fieldVal.Interface(), maps, conv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if name == "" {
+			name = conv(fieldType.Name)
+		}
+
+		v, err := c.obj2Value(fieldVal, conv)
 		if err != nil {
-			return err
+			return withPath(err, name)
 		}
+		maps[name] = v
 	}
 
 	return nil
 }
 
+// 将 Obj2Map 中的字段值 v 转换成 map 中的值
+//
+// 空指针转换成 nil，其它指针转换成指向的值；实现了 [Mapper] 等接口的值由 c.marshal 转换；
+// 结构体转换成 map[string]any；元素中包含结构体的数组和切片转换成 []any，
+// map 转换成 map[string]any；其它类型原样返回。
+func (c *Converter) obj2Value(v reflect.Value, conv FieldConvert) (any, error) {
+	for {
+		isPtr := v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface
+		if isPtr && v.IsNil() {
+			return nil, nil
+		}
+
+		if val, ok, err := c.marshal(v); ok {
+			return val, err
+		}
+
+		if !isPtr {
+			break
+		}
+		v = v.Elem()
+	}
+
+	if !c.needConvert(v.Type()) {
+		return v.Interface(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		m := make(map[string]any)
+		if err := c.obj2Map(v.Interface(), m, conv); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+
+		l := v.Len()
+		items := make([]any, 0, l)
+		for i := 0; i < l; i++ {
+			item, err := c.obj2Value(v.Index(i), conv)
+			if err != nil {
+				return nil, withIndex(err, i)
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := c.String(iter.Key().Interface())
+			if err != nil {
+				return nil, err
+			}
+
+			item, err := c.obj2Value(iter.Value(), conv)
+			if err != nil {
+				return nil, withPath(err, key)
+			}
+			m[key] = item
+		}
+		return m, nil
+	default:
+		return v.Interface(), nil
+	}
+}
+
+// 如果 v 实现了 [Mapper]，或是在指定了 [WithMarshaler] 的情况下实现了
+// [encoding.TextMarshaler] 或 [fmt.Stringer]，返回由接口生成的值，ok 为 true。
+func (c *Converter) marshal(v reflect.Value) (val any, ok bool, err error) {
+	if !v.CanInterface() {
+		return nil, false, nil
+	}
+	if v.Kind() != reflect.Pointer { // 取指针，使其同时包含指针和值的方法集。
+		if v.CanAddr() {
+			v = v.Addr()
+		} else if pt := reflect.PointerTo(v.Type()); pt.Implements(mapperType) ||
+			(c.marshaler && (pt.Implements(textMarshalerType) || pt.Implements(stringerType))) {
+			v = addressable(v).Addr()
+		}
+	}
+	obj := v.Interface()
+
+	if m, ok := obj.(Mapper); ok {
+		if val, err = m.ToMap(); err != nil {
+			err = customError(obj, "map[string]any", err)
+		}
+		return val, true, err
+	}
+
+	if !c.marshaler {
+		return nil, false, nil
+	}
+
+	switch m := obj.(type) {
+	case encoding.TextMarshaler:
+		text, err := m.MarshalText()
+		if err != nil {
+			return nil, true, customError(obj, "string", err)
+		}
+		return string(text), true, nil
+	case fmt.Stringer:
+		return m.String(), true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+// 如果 obj 或是其指针实现了 [Mapper] 则返回该接口
+func asMapper(obj any) (Mapper, bool) {
+	if m, ok := obj.(Mapper); ok {
+		return m, true
+	}
+
+	v := reflect.ValueOf(obj)
+	if v.IsValid() && v.Kind() != reflect.Pointer && reflect.PointerTo(v.Type()).Implements(mapperType) {
+		return addressable(v).Addr().Interface().(Mapper), true
+	}
+	return nil, false
+}
+
+// 返回一个与 v 的值相同且可寻址的副本
+//
+// map 的元素以及由 interface 转换而来的值都不可寻址，无法调用指针接收者的方法。
+func addressable(v reflect.Value) reflect.Value {
+	p := reflect.New(v.Type()).Elem()
+	p.Set(v)
+	return p
+}
+
+var (
+	mapperType        = reflect.TypeOf((*Mapper)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// 判断类型 t 本身或是其元素是否需要由 obj2Value 进一步转换
+//
+// 包括除 [time.Time] 之外的结构体，以及由 c.marshal 处理的类型。
+//
+// 对于 type L []L 之类引用自身的类型，再次遇到已经检测过的类型时即返回 false。
+func (c *Converter) needConvert(t reflect.Type) bool {
+	visited := make(map[reflect.Type]struct{}, 2)
+	for {
+		if _, found := visited[t]; found {
+			return false
+		}
+		visited[t] = struct{}{}
+
+		if implements(t, mapperType) ||
+			(c.marshaler && (implements(t, textMarshalerType) || implements(t, stringerType))) {
+			return true
+		}
+
+		switch t.Kind() {
+		case reflect.Struct:
+			return t != timeType
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return false
+		}
+	}
+}
+
+// t 或是 t 的指针是否实现了接口 i
+func implements(t, i reflect.Type) bool {
+	return t.Implements(i) || (t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(i))
+}
+
 // Obj2Map 将 obj 转换成 map
 //
+// 字段可以通过 conv 标签（可由 [WithTagName] 修改）指定在 map 中的名称，支持 omitempty 和 - 选项，
+// 指定了名称的字段不再经过 conv 转换。
+//
+// 嵌套的结构体（[time.Time] 除外）会被转换成 map[string]any，元素中包含结构体的数组、切片和 map
+// 则分别被转换成 []any 和 map[string]any；空指针转换成 nil，其它指针转换成其指向的值。
+// 实现了 [Mapper] 接口的对象由其 ToMap 方法生成对应的 map。
+//
 // NOTE: 只能转换可导出的数据。
 func Obj2Map(obj any, conv FieldConvert) (map[string]any, error) {
 	ret := make(map[string]any)
@@ -69,71 +361,281 @@ func Obj2Map(obj any, conv FieldConvert) (map[string]any, error) {
 	if conv == nil {
 		conv = defaultFieldConvert
 	}
-	return ret, obj2Map(obj, ret, conv)
+	return ret, defaultConverter.obj2Map(obj, ret, conv)
+}
+
+// Obj2Map 将 obj 转换成 map
+//
+// 字段名称的转换由 [WithKeyConvert] 或是 [WithFieldConvert] 指定。
+func (c *Converter) Obj2Map(obj any) (map[string]any, error) {
+	conv := c.fieldConvert
+	if c.keyConvert != nil {
+		conv = c.keyConvert
+	}
+
+	ret := make(map[string]any)
+	return ret, c.obj2Map(obj, ret, conv)
 }
 
 // Map2Obj 将 map 中的数据转换成一个结构中的数据
+//
+// 各字段的值通过 [Value] 进行转换，无法转换时返回错误，错误中包含了字段的路径。
+// 标签的处理方式与 [Obj2Map] 相同，此外还支持 required 选项，
+// 表示该字段在 map 中必须存在，否则返回 [ErrRequired]，
+// 缺少多个字段时只返回一个错误，其 [Error.Value] 为所有缺少的字段名称。
 func Map2Obj(src any, dest any, conv FieldConvert) error {
-	srcVal, destVal, conv, err := map2ObjCheck(src, dest, conv)
+	c := defaultConverter
+	if conv != nil {
+		c = c.clone(WithFieldConvert(conv))
+	}
+	return c.Map2Obj(src, dest)
+}
+
+// Map2Obj 将 map 中的数据转换成一个结构中的数据
+//
+// 字段名称的转换由 [WithKeyConvert] 或是 [WithFieldConvert] 指定。
+func (c *Converter) Map2Obj(src any, dest any) error {
+	_, err := c.Map2ObjWithMetadata(src, dest)
+	return err
+}
+
+// Metadata 由 [Map2ObjWithMetadata] 返回的转换信息
+//
+// 仅包含顶层对象的信息，嵌套对象的信息不会被记录。
+type Metadata struct {
+	Keys    []string // 已经使用的键名
+	Unused  []string // 没有对应字段的键名
+	Unset   []string // 没有被设置的字段名称
+	Missing []string // 标记为 required 但是没有对应键名的字段名称
+}
+
+// Map2ObjWithMetadata 将 map 中的数据转换成一个结构中的数据并返回转换的相关信息
+//
+// 除了返回的 [Metadata] 之外，与 [Map2Obj] 相同。
+func Map2ObjWithMetadata(src any, dest any, conv FieldConvert) (*Metadata, error) {
+	c := defaultConverter
+	if conv != nil {
+		c = c.clone(WithFieldConvert(conv))
+	}
+	return c.Map2ObjWithMetadata(src, dest)
+}
+
+// Map2ObjWithMetadata 将 map 中的数据转换成一个结构中的数据并返回转换的相关信息
+//
+// 字段名称的转换由 [WithKeyConvert] 或是 [WithFieldConvert] 指定。
+func (c *Converter) Map2ObjWithMetadata(src any, dest any) (*Metadata, error) {
+	srcVal, destVal, err := map2ObjCheck(src, dest)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	keys := srcVal.MapKeys()
-	l := len(keys)
-	for i := 0; i < l; i++ {
-		k := keys[i]
+	meta := &Metadata{}
+	return meta, c.map2Struct(srcVal, destVal, meta)
+}
 
-		if k.Kind() != reflect.String {
-			return errors.New("conv: src 必须为 map[string]any 类型")
-		}
+// 将 map 类型的 srcVal 写入 struct 类型的 destVal
+//
+// meta 用于记录转换的信息，可以为空。
+func (c *Converter) map2Struct(srcVal, destVal reflect.Value, meta *Metadata) error {
+	fields := c.structFields(destVal.Type())
+	set := make([]bool, len(fields))
 
-		srcItemVal := srcVal.MapIndex(k)
-		if !srcItemVal.CanInterface() {
+	keys := make([]string, 0, srcVal.Len())
+	values := make(map[string]reflect.Value, srcVal.Len())
+	iter := srcVal.MapRange()
+	for iter.Next() {
+		if !iter.Key().CanInterface() || !iter.Value().CanInterface() {
 			continue
 		}
 
-		fieldValue := destVal.FieldByName(conv(k.String()))
-		if !fieldValue.CanSet() {
+		key, err := c.String(iter.Key().Interface()) // 非字符串的键名，比如 yaml 的 map[any]any。
+		if err != nil {
+			return err
+		}
+		if _, exists := values[key]; !exists {
+			keys = append(keys, key)
+		}
+		values[key] = iter.Value()
+	}
+	sort.Strings(keys) // 保证错误信息的稳定性
+
+	for _, key := range keys {
+		srcItemVal := values[key]
+
+		index := c.fieldByKey(fields, key)
+		if index < 0 {
+			if c.disallowUnknownKeys {
+				err := newError(srcItemVal.Interface(), destVal.Type().String(), ErrUnknownKey, nil)
+				return withPath(err, key)
+			}
+			if meta != nil {
+				meta.Unused = append(meta.Unused, key)
+			}
 			continue
 		}
 
-		fieldType := fieldValue.Type()
-		srcItemType := srcItemVal.Type()
-		if fieldType.Kind() == srcItemType.Kind() { // 类型相同
-			fieldValue.Set(srcItemVal)
+		fieldValue, err := fieldByIndex(destVal, fields[index].Index)
+		if err != nil {
+			return err
+		}
+
+		// 与 Value 采用相同的转换规则，空的指针、切片和 map 会被自动分配。
+		if err := c.Value(srcItemVal.Interface(), fieldValue); err != nil {
+			return withPath(err, key)
+		}
+
+		set[index] = true
+		if meta != nil {
+			meta.Keys = append(meta.Keys, key)
+		}
+	}
+
+	var missing []string
+	for i, f := range fields {
+		if set[i] {
 			continue
 		}
 
-		// 如果 src 中元素的类型为 Interface，则再对该值使用 reflect.ValueOf
-		// 就能正常地使用类型断言和其它判断了。
-		if srcItemType.Kind() == reflect.Interface {
-			srcItemVal = reflect.ValueOf(srcItemVal.Interface())
-			srcItemType = srcItemVal.Type()
+		if tag := c.parseTag(f); tag.required {
+			name := tag.name
+			if name == "" {
+				name = f.Name
+			}
+			missing = append(missing, name)
+		}
+
+		if meta != nil {
+			meta.Unset = append(meta.Unset, f.Name)
 		}
+	}
 
-		if !srcItemVal.CanInterface() {
+	if len(missing) > 0 {
+		if meta != nil {
+			meta.Missing = missing
+		}
+		return newError(missing, destVal.Type().String(), ErrRequired, nil)
+	}
+	return nil
+}
+
+// 返回 t 中所有可以与 map 中的键名对应的字段
+//
+// 被忽略的字段、未指定名称的匿名字段（其字段会被提升）以及不可导出的字段不会被包含。
+func (c *Converter) structFields(t reflect.Type) []reflect.StructField {
+	fields := make([]reflect.StructField, 0, t.NumField())
+	var skip [][]int // 被忽略或是指定了名称的匿名字段，其子字段也需要忽略。
+
+LOOP:
+	for _, f := range reflect.VisibleFields(t) {
+		for _, index := range skip {
+			if len(f.Index) > len(index) && equalIndex(f.Index[:len(index)], index) {
+				continue LOOP
+			}
+		}
+
+		tag := c.parseTag(f)
+		if tag.ignore {
+			skip = append(skip, f.Index)
 			continue
 		}
 
-		if srcItemType.Kind() == reflect.Map { // 含有子元素
-			err := Map2Obj(srcItemVal.Interface(), fieldValue.Interface(), conv)
-			if err != nil {
-				return err
+		if f.Anonymous {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
 			}
+			if ft.Kind() == reflect.Struct {
+				if tag.name == "" { // 未指定名称的匿名字段，由其子字段代替。
+					continue
+				}
+				skip = append(skip, f.Index)
+			}
+		}
+
+		if f.IsExported() {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+func equalIndex(i1, i2 []int) bool {
+	for i, v := range i1 {
+		if i2[i] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// 查找 fields 中与 key 对应的字段，返回其在 fields 中的下标，找不到返回 -1。
+//
+// 优先匹配标签中指定的名称，之后才是字段名，指定了名称的字段不参与字段名的匹配。
+// 精确匹配失败之后，才会采用 c.fieldMatch 进行匹配。
+func (c *Converter) fieldByKey(fields []reflect.StructField, key string) int {
+	if index := c.matchField(fields, key, func(s string) string { return s }); index >= 0 {
+		return index
+	}
+
+	if c.fieldMatch != MatchExact {
+		return c.matchField(fields, key, c.fieldMatch.normalize)
+	}
+	return -1
+}
+
+// 在 fields 中查找经 normalize 处理之后与 key 相同的字段
+//
+// 未指定名称的字段，如果存在 c.keyConvert，则比较经其转换后的字段名与 key，
+// 否则比较字段名与经 c.fieldConvert 转换后的 key。
+func (c *Converter) matchField(fields []reflect.StructField, key string, normalize func(string) string) int {
+	nkey := normalize(key)
+	for i, f := range fields {
+		if n := c.parseTag(f).name; n != "" && normalize(n) == nkey {
+			return i
+		}
+	}
+
+	var name string
+	if c.keyConvert == nil {
+		name = normalize(c.fieldConvert(key))
+	}
+	for i, f := range fields {
+		if c.parseTag(f).name != "" {
 			continue
 		}
 
-		if srcItemType.ConvertibleTo(fieldType) { // 类型之间可转换
-			fieldValue.Set(srcItemVal.Convert(fieldType))
+		if c.keyConvert != nil {
+			if normalize(c.keyConvert(f.Name)) == nkey {
+				return i
+			}
+		} else if normalize(f.Name) == name {
+			return i
 		}
 	}
 
-	return nil
+	return -1
+}
+
+// 获取 v 中由 index 指定的字段，经过的空指针会被自动分配。
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("conv: 无法为不可导出的匿名字段 %s 分配内存", v.Type())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
 }
 
 // 对 map2Obj 各个参数的检测，并返回正确的值或是错误信息。
-func map2ObjCheck(src any, dest any, conv FieldConvert) (srcVal reflect.Value, destVal reflect.Value, fun FieldConvert, err error) {
+func map2ObjCheck(src any, dest any) (srcVal reflect.Value, destVal reflect.Value, err error) {
 	destVal = reflect.ValueOf(dest)
 	if destVal.Kind() != reflect.Pointer {
 		err = fmt.Errorf("conv: dest 必须为一个 struct 对象的指针，实际类型为[%v]", destVal.Type())
@@ -154,13 +656,6 @@ func map2ObjCheck(src any, dest any, conv FieldConvert) (srcVal reflect.Value, d
 
 	if srcVal.Kind() != reflect.Map {
 		err = fmt.Errorf("conv: src 必须为 map 类型或是 map 指针，实际类型为[%v]", srcVal.Type())
-		return
-	}
-
-	if conv == nil {
-		fun = defaultFieldConvert
-	} else {
-		fun = conv
 	}
 	return
 }
@@ -5,6 +5,7 @@
 package conv
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -124,3 +125,487 @@ func TestObj2Map(t *testing.T) {
 	sub = m["SUB"].(map[string]any)
 	as.Equal(sub["ID"], 5)
 }
+
+func TestObj2Map_nested(t *testing.T) {
+	a := assert.New(t, false)
+
+	type item struct {
+		ID   int
+		Name *string
+	}
+
+	type obj struct {
+		*A1
+		Num   *int
+		Nil   *A1
+		Any   any
+		Items []item
+		Ptrs  []*item
+		Array [2]item
+		Map   map[int]item
+		Nums  []int
+		Empty []item
+	}
+
+	name := "n"
+	num := 5
+	m, err := Obj2Map(&obj{
+		Num:   &num,
+		Any:   &item{ID: 1},
+		Items: []item{{ID: 1, Name: &name}},
+		Ptrs:  []*item{{ID: 2}, nil},
+		Array: [2]item{{ID: 3}},
+		Map:   map[int]item{4: {ID: 4}},
+		Nums:  []int{1, 2},
+	}, nil)
+	a.NotError(err).Equal(m, map[string]any{
+		"Num":   5,
+		"Nil":   nil,
+		"Any":   map[string]any{"ID": 1, "Name": nil},
+		"Items": []any{map[string]any{"ID": 1, "Name": "n"}},
+		"Ptrs":  []any{map[string]any{"ID": 2, "Name": nil}, nil},
+		"Array": []any{map[string]any{"ID": 3, "Name": nil}, map[string]any{"ID": 0, "Name": nil}},
+		"Map":   map[string]any{"4": map[string]any{"ID": 4, "Name": nil}},
+		"Nums":  []int{1, 2},
+		"Empty": nil,
+	})
+
+	// 嵌套对象中的字段名同样经过转换
+	m, err = Obj2Map(&obj{Items: []item{{ID: 1}}}, ToUpperFieldConv)
+	a.NotError(err).Equal(m["ITEMS"], []any{map[string]any{"ID": 1, "NAME": nil}})
+
+	// 错误中包含路径
+	type invalid struct {
+		Items []map[any]A1
+	}
+	_, err = Obj2Map(&invalid{Items: []map[any]A1{{struct{}{}: {}}}}, nil)
+	a.Error(err).ErrorIs(err, ErrUnsupported)
+	var e *Error
+	a.True(errors.As(err, &e)).Equal(e.Path, "Items[0]")
+}
+
+type (
+	tree     map[string]tree
+	list     []list
+	ptrList  []*ptrList
+	treeNode struct {
+		Children []*treeNode
+	}
+)
+
+func TestObj2Map_recursiveType(t *testing.T) {
+	a := assert.New(t, false)
+
+	type obj struct {
+		Tree tree
+		List list
+		Ptrs ptrList
+		Node treeNode
+	}
+
+	m, err := Obj2Map(&obj{
+		Tree: tree{"a": tree{}},
+		List: list{list{}},
+		Ptrs: ptrList{nil},
+		Node: treeNode{Children: []*treeNode{{}}},
+	}, nil)
+	a.NotError(err).
+		Equal(m["Tree"], tree{"a": tree{}}).
+		Equal(m["List"], list{list{}}).
+		Equal(m["Ptrs"], ptrList{nil}).
+		Equal(m["Node"], map[string]any{"Children": []any{map[string]any{"Children": nil}}})
+}
+
+type mapper struct {
+	ID  int
+	err error
+}
+
+func (m *mapper) ToMap() (map[string]any, error) {
+	return map[string]any{"id": m.ID}, m.err
+}
+
+func TestObj2Map_mapper(t *testing.T) {
+	a := assert.New(t, false)
+
+	type obj struct {
+		Value   mapper
+		Ptr     *mapper
+		Nil     *mapper
+		Mappers []mapper
+	}
+
+	m, err := Obj2Map(&obj{
+		Value:   mapper{ID: 2},
+		Ptr:     &mapper{ID: 3},
+		Mappers: []mapper{{ID: 4}},
+	}, nil)
+	a.NotError(err).Equal(m, map[string]any{
+		"Nil":     nil,
+		"Value":   map[string]any{"id": 2},
+		"Ptr":     map[string]any{"id": 3},
+		"Mappers": []any{map[string]any{"id": 4}},
+	})
+
+	m, err = Obj2Map(&mapper{ID: 5}, nil)
+	a.NotError(err).Equal(m, map[string]any{"id": 5})
+
+	// 不可寻址的值
+	m, err = Obj2Map(mapper{ID: 7}, nil)
+	a.NotError(err).Equal(m, map[string]any{"id": 7})
+
+	m, err = Obj2Map(obj{Value: mapper{ID: 8}}, nil)
+	a.NotError(err).Equal(m["Value"], map[string]any{"id": 8})
+
+	type mappers struct {
+		M map[string]mapper
+	}
+	m, err = Obj2Map(&mappers{M: map[string]mapper{"a": {ID: 9}}}, nil)
+	a.NotError(err).Equal(m["M"], map[string]any{"a": map[string]any{"id": 9}})
+
+	// 匿名字段的 ToMap 会被提升为外层对象的方法
+	type embedded struct {
+		*mapper
+		Name string
+	}
+	m, err = Obj2Map(&embedded{mapper: &mapper{ID: 6}, Name: "n"}, nil)
+	a.NotError(err).Equal(m, map[string]any{"id": 6})
+
+	errMapper := errors.New("err")
+	_, err = Obj2Map(&obj{Ptr: &mapper{err: errMapper}}, nil)
+	a.ErrorIs(err, ErrCustom).ErrorIs(err, errMapper)
+	var e *Error
+	a.True(errors.As(err, &e)).Equal(e.Path, "Ptr").Equal(e.Kind, ErrCustom)
+
+	_, err = Obj2Map(&mapper{err: errMapper}, nil)
+	a.ErrorIs(err, ErrCustom).ErrorIs(err, errMapper)
+}
+
+type tagObject struct {
+	ID       int    `conv:"id"`
+	Name     string `json:"name,omitempty"`
+	Password string `conv:"-"`
+	Age      int    `conv:",omitempty"`
+	Email    string `conv:"email" json:"mail"`
+	Dash     string `json:"-,"`
+	Sub      *A1    `conv:"sub,omitempty"`
+}
+
+func TestObj2Map_tag(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := New(WithTagName("conv", "json"))
+	m, err := c.Obj2Map(&tagObject{ID: 1, Name: "n", Password: "p", Email: "e", Dash: "d"})
+	a.NotError(err).Equal(m, map[string]any{
+		"id":    1,
+		"name":  "n",
+		"email": "e",
+		"-":     "d",
+	})
+
+	c = New(WithTagName("conv", "json"), WithFieldConvert(ToUpperFieldConv))
+	m, err = c.Obj2Map(&tagObject{Age: 5, Sub: &A1{ID: 2}})
+	a.NotError(err).Equal(m, map[string]any{
+		"id":    0,
+		"AGE":   5,
+		"email": "",
+		"-":     "",
+		"sub":   map[string]any{"ID": 2, "NAME": ""},
+	})
+}
+
+func TestMap2Obj_tag(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := New(WithTagName("conv", "json"))
+	obj := &tagObject{}
+	a.NotError(c.Map2Obj(map[string]any{
+		"id":       1,
+		"name":     "n",
+		"Password": "p",
+		"Age":      5,
+		"email":    "e",
+		"mail":     "m",
+		"Email":    "E",
+		"-":        "d",
+	}, obj))
+	a.Equal(obj, &tagObject{ID: 1, Name: "n", Age: 5, Email: "e", Dash: "d"})
+
+	// 指定了名称的字段，不再匹配字段名。
+	obj = &tagObject{}
+	a.NotError(c.Map2Obj(map[string]any{"ID": 1, "Name": "n"}, obj))
+	a.Equal(obj, &tagObject{})
+}
+
+func TestObj2Map_jsonTag(t *testing.T) {
+	a := assert.New(t, false)
+
+	type obj struct {
+		Name  string `json:"name"`
+		Token string `json:"-"`
+		Age   int
+	}
+
+	// 默认不读取 json 标签
+	m, err := Obj2Map(&obj{Name: "n", Token: "t", Age: 5}, nil)
+	a.NotError(err).Equal(m, map[string]any{"Name": "n", "Token": "t", "Age": 5})
+
+	o := &obj{}
+	a.NotError(Map2Obj(map[string]any{"Name": "n", "Token": "t", "name": "x"}, o, nil))
+	a.Equal(o, &obj{Name: "n", Token: "t"})
+}
+
+type server struct {
+	Host string
+	Port int
+}
+
+type config struct {
+	Servers  []server
+	Backup   *server
+	Named    map[string]server
+	Pointers []*server
+	Nested   struct {
+		Primary server
+	}
+}
+
+func TestMap2Obj_recursive(t *testing.T) {
+	a := assert.New(t, false)
+
+	m := map[string]any{
+		"Servers": []map[string]any{
+			{"Host": "a", "Port": 1},
+			{"Host": "b", "Port": 2},
+		},
+		"Backup": map[string]any{"Host": "c"},
+		"Named": map[string]map[string]any{
+			"d": {"Host": "d", "Port": 4},
+		},
+		"Pointers": []any{map[string]any{"Host": "e"}},
+		"Nested": map[string]any{
+			"Primary": map[string]any{"Host": "f", "Port": 6},
+		},
+	}
+
+	conf := &config{}
+	a.NotError(Map2Obj(m, conf, nil))
+	a.Equal(conf.Servers, []server{{"a", 1}, {"b", 2}}).
+		Equal(conf.Backup, &server{Host: "c"}).
+		Equal(conf.Named, map[string]server{"d": {"d", 4}}).
+		Equal(conf.Pointers, []*server{{Host: "e"}}).
+		Equal(conf.Nested.Primary, server{"f", 6})
+
+	// 已有的值会被保留
+	conf = &config{Backup: &server{Host: "x", Port: 9}}
+	a.NotError(Map2Obj(map[string]any{"Backup": map[string]any{"Host": "y"}}, conf, nil))
+	a.Equal(conf.Backup, &server{Host: "y", Port: 9})
+
+	// FieldConvert 作用于所有层级
+	type upper struct{ SUB *C }
+	u := &upper{}
+	a.NotError(Map2Obj(map[string]any{"sub": map[string]any{"password": "p"}}, u, ToUpperFieldConv))
+	a.Equal(u.SUB.PASSWORD, "p")
+}
+
+func TestMap2Obj_value(t *testing.T) {
+	a := assert.New(t, false)
+
+	type obj struct {
+		Port    int
+		Name    string
+		Enabled bool
+		Ratio   float32
+		Tags    []string
+		Any     any
+	}
+
+	o := &obj{}
+	a.NotError(Map2Obj(map[string]any{
+		"Port":    "8080",
+		"Name":    8080,
+		"Enabled": "on",
+		"Ratio":   "0.5",
+		"Tags":    []any{1, "2"},
+		"Any":     []int{1},
+	}, o, nil))
+	a.Equal(o, &obj{Port: 8080, Name: "8080", Enabled: true, Ratio: 0.5, Tags: []string{"1", "2"}, Any: []int{1}})
+
+	err := Map2Obj(map[string]any{"Port": "x"}, o, nil)
+	a.ErrorIs(err, ErrSyntax).
+		ErrorString(err, "conv: Port: string:x 无法转换成 int64 类型: 格式错误")
+
+	conf := &config{}
+	err = Map2Obj(map[string]any{
+		"Servers": []any{
+			map[string]any{"Port": 1},
+			map[string]any{"Port": "x"},
+		},
+	}, conf, nil)
+	a.ErrorIs(err, ErrSyntax)
+	var ce *Error
+	a.True(errors.As(err, &ce)).Equal(ce.Path, "Servers[1].Port")
+
+	err = Map2Obj(map[string]any{"Named": map[string]any{"d": map[string]any{"Port": "y"}}}, conf, nil)
+	a.True(errors.As(err, &ce)).Equal(ce.Path, "Named.d.Port")
+}
+
+func TestMap2ObjWithMetadata(t *testing.T) {
+	a := assert.New(t, false)
+
+	obj := &b1{}
+	meta, err := Map2ObjWithMetadata(map[string]any{
+		"ID":    5,
+		"Name":  "n",
+		"lower": "lower",
+		"Other": 1,
+	}, obj, nil)
+	a.NotError(err).Equal(meta, &Metadata{
+		Keys:   []string{"ID", "Name"},
+		Unused: []string{"Other", "lower"},
+		Unset:  []string{"Password"},
+	})
+	a.Equal(obj.ID, 5).Equal(obj.Name, "n")
+
+	// 带转换函数
+	objC := &C{}
+	meta, err = Map2ObjWithMetadata(map[string]any{"password": "p"}, objC, ToUpperFieldConv)
+	a.NotError(err).Equal(meta.Keys, []string{"password"}).Equal(meta.Unset, []string{"SUB"})
+
+	_, err = Map2ObjWithMetadata(5, objC, nil)
+	a.Error(err)
+}
+
+func TestWithDisallowUnknownKeys(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := New(WithDisallowUnknownKeys())
+	obj := &A1{}
+	a.NotError(c.Map2Obj(map[string]any{"ID": 1}, obj))
+
+	err := c.Map2Obj(map[string]any{"ID": 1, "Other": 2}, obj)
+	a.ErrorIs(err, ErrUnknownKey).
+		ErrorString(err, "conv: Other: int:2 无法转换成 conv.A1 类型: 未知的键名")
+
+	// 嵌套
+	conf := &config{}
+	err = c.Map2Obj(map[string]any{"Servers": []any{map[string]any{"Hots": "x"}}}, conf)
+	a.ErrorIs(err, ErrUnknownKey)
+	var ce *Error
+	a.True(errors.As(err, &ce)).Equal(ce.Path, "Servers[0].Hots")
+
+	// 被忽略的字段
+	err = c.Map2Obj(map[string]any{"Password": "p"}, &tagObject{})
+	a.ErrorIs(err, ErrUnknownKey)
+}
+
+func TestMap2Obj_required(t *testing.T) {
+	a := assert.New(t, false)
+
+	type sub struct {
+		Host string `conv:"host,required"`
+	}
+	type obj struct {
+		Port int `conv:",required"`
+		Sub  *sub
+	}
+
+	o := &obj{}
+	a.NotError(Map2Obj(map[string]any{"Port": 1}, o, nil))
+
+	err := Map2Obj(map[string]any{}, o, nil)
+	a.ErrorIs(err, ErrRequired).
+		ErrorString(err, "conv: []string:[Port] 无法转换成 conv.obj 类型: 缺少必要的值")
+
+	err = Map2Obj(map[string]any{"Port": 1, "Sub": map[string]any{}}, o, nil)
+	var ce *Error
+	a.ErrorIs(err, ErrRequired).True(errors.As(err, &ce)).
+		Equal(ce.Path, "Sub").
+		Equal(ce.Value, []string{"host"})
+
+	// 缺少多个字段时，一次性返回所有字段。
+	type multi struct {
+		ID   int    `conv:"id,required"`
+		Name string `conv:",required"`
+		Age  int
+	}
+	meta, err := Map2ObjWithMetadata(map[string]any{"Other": 1}, &multi{}, nil)
+	a.ErrorIs(err, ErrRequired).
+		ErrorString(err, "conv: []string:[id Name] 无法转换成 conv.multi 类型: 缺少必要的值").
+		Equal(meta.Missing, []string{"id", "Name"}).
+		Equal(meta.Unset, []string{"ID", "Name", "Age"}).
+		Equal(meta.Unused, []string{"Other"})
+}
+
+func TestMap2Obj_embedded(t *testing.T) {
+	a := assert.New(t, false)
+
+	type named struct {
+		A1 `conv:"a1"`
+	}
+	type ptr struct {
+		*A1
+	}
+
+	n := &named{}
+	meta, err := Map2ObjWithMetadata(map[string]any{"a1": map[string]any{"ID": 1}, "ID": 2}, n, nil)
+	a.NotError(err).Equal(n.ID, 1).Equal(meta.Unused, []string{"ID"})
+
+	p := &ptr{}
+	a.NotError(Map2Obj(map[string]any{"ID": 3}, p, nil))
+	a.Equal(p.A1, &A1{ID: 3})
+}
+
+func TestMap2Obj_keys(t *testing.T) {
+	a := assert.New(t, false)
+
+	type obj struct {
+		ID         int
+		HTTPServer string
+		Name       string `conv:"user_name"`
+	}
+
+	// 非字符串的键名
+	o := &obj{}
+	a.NotError(Map2Obj(map[any]any{"ID": 1, "HTTPServer": "s"}, o, nil))
+	a.Equal(o, &obj{ID: 1, HTTPServer: "s"})
+
+	type num struct {
+		V1 int `conv:"1"`
+		V2 int `conv:"2"`
+	}
+	n := &num{}
+	a.NotError(Map2Obj(map[int]int{1: 1, 2: 2}, n, nil))
+	a.Equal(n, &num{V1: 1, V2: 2})
+
+	a.ErrorIs(Map2Obj(map[any]any{struct{}{}: 1}, n, nil), ErrUnsupported)
+
+	// 匹配方式
+	o = &obj{}
+	a.NotError(Map2Obj(map[string]any{"id": 1, "httpserver": "s"}, o, nil))
+	a.Equal(o, &obj{})
+
+	c := New(WithFieldMatch(MatchCaseInsensitive))
+	a.NotError(c.Map2Obj(map[string]any{"id": 1, "httpserver": "s", "USER_NAME": "n"}, o))
+	a.Equal(o, &obj{ID: 1, HTTPServer: "s", Name: "n"})
+
+	o = &obj{}
+	c = New(WithFieldMatch(MatchSnakeCase))
+	a.NotError(c.Map2Obj(map[string]any{"id": 1, "http_server": "s", "username": "n"}, o))
+	a.Equal(o, &obj{ID: 1, HTTPServer: "s", Name: "n"})
+
+	o = &obj{}
+	c = New(WithFieldMatch(MatchKebabCase))
+	a.NotError(c.Map2Obj(map[string]any{"id": 1, "http-server": "s", "http_server": "x"}, o))
+	a.Equal(o, &obj{ID: 1, HTTPServer: "s"})
+
+	// 精确匹配优先
+	type dup struct {
+		Name string
+		NAME string
+	}
+	d := &dup{}
+	c = New(WithFieldMatch(MatchCaseInsensitive))
+	a.NotError(c.Map2Obj(map[string]any{"NAME": "upper", "name": "lower"}, d))
+	a.Equal(d, &dup{Name: "lower", NAME: "upper"})
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2014-2026 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package conv
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+type registeredConverter struct {
+	source reflect.Type
+	fn     func(any) (reflect.Value, error)
+}
+
+var registry = struct {
+	sync.RWMutex
+	converters map[reflect.Type][]*registeredConverter // 以目标类型为键名
+}{
+	converters: make(map[reflect.Type][]*registeredConverter, 10),
+}
+
+// Register 注册从 S 到 T 的转换函数
+//
+// 注册之后，[Value]、[To]、[SliceOf] 和 [Map2Obj] 在目标类型为 T 且源值为 S 类型时，
+// 都将优先调用 f 进行转换。S 可以是接口，此时所有实现了 S 的类型都能匹配，
+// 但是类型完全相同的注册项优先。
+// 同一组类型重复注册时，后者覆盖前者。
+//
+// f 返回的错误如果不是 [Error] 类型，会被包装成 Kind 为 [ErrCustom] 的 [Error]，
+// 原始错误保存在其 Err 字段，依然可以通过 [errors.Is] 进行判断。
+func Register[S, T any](f func(S) (T, error)) {
+	src := reflect.TypeOf((*S)(nil)).Elem() // TODO(go1.22) 可用 reflect.TypeFor 代替
+	dest := reflect.TypeOf((*T)(nil)).Elem()
+
+	c := &registeredConverter{
+		source: src,
+		fn: func(v any) (reflect.Value, error) {
+			ret, err := f(v.(S))
+			return reflect.ValueOf(&ret).Elem(), err
+		},
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	cs := registry.converters[dest]
+	for i, item := range cs {
+		if item.source == src {
+			cs[i] = c
+			return
+		}
+	}
+	registry.converters[dest] = append(cs, c)
+}
+
+// 查找从 src 到 dest 的转换函数，找不到返回 nil。
+func lookupConverter(src, dest reflect.Type) *registeredConverter {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	cs := registry.converters[dest]
+	if len(cs) == 0 {
+		return nil
+	}
+
+	for _, c := range cs {
+		if c.source == src {
+			return c
+		}
+	}
+
+	for _, c := range cs {
+		if c.source.Kind() == reflect.Interface && src.Implements(c.source) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// 如果存在从 source 到 target 的注册项，则调用其进行转换。
+//
+// ok 表示是否找到了对应的注册项。
+func valueRegistered(source any, target reflect.Value) (ok bool, err error) {
+	if source == nil || !target.CanSet() {
+		return false, nil
+	}
+
+	c := lookupConverter(reflect.TypeOf(source), target.Type())
+	if c == nil {
+		return false, nil
+	}
+
+	ret, err := c.fn(source)
+	if err != nil {
+		var ce *Error
+		if errors.As(err, &ce) {
+			return true, err
+		}
+		return true, customError(source, target.Type().String(), err)
+	}
+
+	target.Set(ret)
+	return true, nil
+}
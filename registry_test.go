@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2014-2026 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package conv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+type money int64
+
+type userID struct{ id int }
+
+var errInvalidMoney = errors.New("invalid money")
+
+func TestRegister(t *testing.T) {
+	a := assert.New(t, false)
+
+	Register(func(s string) (money, error) {
+		if !strings.HasPrefix(s, "$") {
+			return 0, errInvalidMoney
+		}
+		v, err := Int64(s[1:])
+		return money(v * 100), err
+	})
+	Register(func(s fmt.Stringer) (userID, error) {
+		return userID{id: len(s.String())}, nil
+	})
+
+	// Value
+	var m money
+	a.NotError(Value("$5", reflect.ValueOf(&m))).Equal(m, 500)
+	a.NotError(Value(int64(7), reflect.ValueOf(&m))).Equal(m, 7) // 未注册的类型
+
+	err := Value("5", reflect.ValueOf(&m))
+	a.ErrorIs(err, errInvalidMoney)
+	var ce *Error
+	a.True(errors.As(err, &ce)).
+		Equal(ce.Target, "conv.money").
+		Equal(ce.Kind, ErrCustom).
+		Equal(ce.Err, errInvalidMoney).
+		ErrorIs(err, ErrCustom).
+		ErrorString(err, "conv: string:5 无法转换成 conv.money 类型: invalid money")
+
+	// To
+	p, err := To[*money]("$3")
+	a.NotError(err).Equal(*p, 300)
+
+	// 接口
+	id, err := To[userID](reflect.TypeOf(m))
+	a.NotError(err).Equal(id, userID{id: len("conv.money")})
+
+	// SliceOf
+	ms, err := SliceOf[money]([]string{"$1", "$2"})
+	a.NotError(err).Equal(ms, []money{100, 200})
+	_, err = SliceOf[money]([]string{"$1", "2"})
+	a.ErrorIs(err, errInvalidMoney).True(errors.As(err, &ce)).Equal(ce.Path, "[1]")
+
+	// SliceOf 字符串中的字节
+	type letter struct{ upper byte }
+	Register(func(b byte) (letter, error) { return letter{upper: b - 'a' + 'A'}, nil })
+	ls, err := SliceOf[letter]("ab")
+	a.NotError(err).Equal(ls, []letter{{'A'}, {'B'}})
+
+	// Map2Obj
+	type obj struct{ Price money }
+	o := &obj{}
+	a.NotError(Map2Obj(map[string]any{"Price": "$9"}, o, nil)).Equal(o.Price, 900)
+	err = Map2Obj(map[string]any{"Price": "9"}, o, nil)
+	a.ErrorIs(err, errInvalidMoney).True(errors.As(err, &ce)).Equal(ce.Path, "Price")
+
+	// 覆盖
+	Register(func(s string) (money, error) { return 1, nil })
+	a.NotError(Value("5", reflect.ValueOf(&m))).Equal(m, 1)
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2014-2026 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package conv
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const timeTarget = "time.Time"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// 默认的时间格式
+var defaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Time 将 val 转换成 [time.Time] 类型或是在无法转换的情况下返回 error
+//
+// 字符串依次尝试 [WithTimeLayouts] 指定的格式，默认支持 RFC3339 以及 2006-01-02 等格式，
+// 不包含时区信息的字符串由 [WithLocation] 指定其时区，默认为 UTC。
+//
+// 数值以及数值格式的字符串表示 Unix 时间戳，单位由 [WithTimestampUnit] 指定，
+// 默认根据数值的大小自动判断是秒、毫秒、微秒还是纳秒。
+// 字符串只有在无法由 [WithTimeLayouts] 指定的格式解析时，才会被当作时间戳，
+// 所以 20060102 之类仅包含数字的格式优先于时间戳。
+//
+// o 用于在默认规则的基础上修改转换规则。
+func Time(val any, o ...Option) (time.Time, error) {
+	c := defaultConverter
+	if len(o) > 0 {
+		c = c.clone(o...)
+	}
+	return c.Time(val)
+}
+
+// Time 将 val 转换成 [time.Time] 类型或是在无法转换的情况下返回 error
+func (c *Converter) Time(val any) (time.Time, error) {
+	switch ret := val.(type) {
+	case time.Time:
+		return ret, nil
+	case int64:
+		return c.int2Time(ret), nil
+	case uint64:
+		if ret > math.MaxInt64 {
+			return time.Time{}, rangeError(ret, timeTarget)
+		}
+		return c.int2Time(int64(ret)), nil
+	case float32:
+		return c.float2Time(ret, float64(ret))
+	case float64:
+		return c.float2Time(ret, ret)
+	case []byte:
+		return c.str2Time(ret, string(ret))
+	case string:
+		return c.str2Time(ret, ret)
+	case bool:
+		return time.Time{}, typeError(ret, timeTarget)
+	default:
+		if v, ok := indirect(val); ok {
+			if v == nil {
+				return time.Time{}, c.nilError(val, timeTarget)
+			}
+			return c.Time(v)
+		}
+
+		if u, ok := underlying(val); ok {
+			ret, err := c.Time(u)
+			return ret, replaceErrorValue(err, val)
+		}
+		return time.Time{}, typeError(val, timeTarget)
+	}
+}
+
+// MustTime 将 val 转换成 [time.Time] 类型或是在无法转换的情况下返回 def 参数
+func MustTime(val any, def ...time.Time) time.Time {
+	if ret, err := Time(val); err == nil {
+		return ret
+	}
+	return def[0]
+}
+
+// 将 t 转换成由 c.timeFormat 指定格式的字符串
+func (c *Converter) formatTime(t time.Time) string {
+	if c.timeFormat == "" {
+		return t.String()
+	}
+	return t.Format(c.timeFormat)
+}
+
+// 根据时间戳 n 的大小判断其单位
+//
+// 秒级的时间戳在 5138 年之前都不会超过 1e11，其它单位依此类推。
+func (c *Converter) unixUnit(n float64) time.Duration {
+	if c.timestampUnit > 0 {
+		return c.timestampUnit
+	}
+
+	switch n = math.Abs(n); {
+	case n < 1e11:
+		return time.Second
+	case n < 1e14:
+		return time.Millisecond
+	case n < 1e17:
+		return time.Microsecond
+	default:
+		return time.Nanosecond
+	}
+}
+
+func (c *Converter) int2Time(n int64) time.Time {
+	var t time.Time
+	switch c.unixUnit(float64(n)) {
+	case time.Second:
+		t = time.Unix(n, 0)
+	case time.Millisecond:
+		t = time.UnixMilli(n)
+	case time.Microsecond:
+		t = time.UnixMicro(n)
+	default:
+		t = time.Unix(0, n)
+	}
+	return t.In(c.location)
+}
+
+func (c *Converter) float2Time(val any, f float64) (time.Time, error) {
+	ns := f * float64(c.unixUnit(f))
+	if math.IsNaN(ns) || ns < math.MinInt64 || ns >= math.MaxInt64 {
+		return time.Time{}, rangeError(val, timeTarget)
+	}
+	return time.Unix(0, int64(math.Round(ns))).In(c.location), nil
+}
+
+func (c *Converter) str2Time(val any, str string) (time.Time, error) {
+	str = strings.TrimSpace(normalizeDigits(str))
+
+	// 优先采用 c.timeLayouts，否则 20060102 之类仅包含数字的格式会被当作时间戳。
+	var err error
+	for _, layout := range c.timeLayouts {
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, str, c.location); err == nil {
+			return t, nil
+		}
+	}
+
+	if isNumeric(str) {
+		if !strings.ContainsAny(str, ".eE") {
+			n, err := strconv.ParseInt(str, 10, 64)
+			if err == nil {
+				return c.int2Time(n), nil
+			} else if errors.Is(err, strconv.ErrRange) {
+				return time.Time{}, rangeError(val, timeTarget)
+			}
+		} else if f, err := strconv.ParseFloat(str, 64); err == nil {
+			return c.float2Time(val, f)
+		}
+	}
+
+	return time.Time{}, syntaxError(val, timeTarget, err)
+}
+
+// 判断 str 是否为十进制的数值格式
+//
+// 2006-01-02 之类的日期不能被当作数值。
+func isNumeric(str string) bool {
+	if str == "" {
+		return false
+	}
+
+	s := str
+	if s[0] == '-' || s[0] == '+' {
+		s = s[1:]
+	}
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch b := s[i]; {
+		case b >= '0' && b <= '9', b == '.', b == 'e', b == 'E':
+		case (b == '-' || b == '+') && (s[i-1] == 'e' || s[i-1] == 'E'):
+		default:
+			return false
+		}
+	}
+	return true
+}
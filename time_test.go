@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2014-2026 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package conv
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestTime(t *testing.T) {
+	a := assert.New(t, false)
+
+	sec := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	nano := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	type timestamp int64
+
+	for val, want := range map[any]time.Time{
+		sec:                              sec,
+		&sec:                             sec,
+		"2024-01-02T03:04:05Z":           sec,
+		"2024-01-02T11:04:05+08:00":      sec,
+		"2024-01-02T03:04:05.123456789Z": nano,
+		"2024-01-02T03:04:05":            sec,
+		" 2024-01-02 03:04:05 ":          sec,
+		"2024-01-02":                     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		sec.Unix():                       sec,
+		int(sec.Unix()):                  sec,
+		uint64(sec.Unix()):               sec,
+		timestamp(sec.Unix()):            sec,
+		sec.UnixMilli():                  sec,
+		nano.UnixMicro():                 nano.Truncate(time.Microsecond),
+		nano.UnixNano():                  nano,
+		float64(sec.Unix()) + 0.5:        sec.Add(500 * time.Millisecond),
+		"1704164645":                     sec,
+		"1704164645000":                  sec,
+		"1704164645.5":                   sec.Add(500 * time.Millisecond),
+		"1.704164645e9":                  sec,
+		"-1":                             time.Unix(-1, 0),
+	} {
+		v, err := Time(val)
+		a.NotError(err, val).True(v.Equal(want), val, v, want)
+	}
+
+	v, err := Time([]byte("2024-01-02T03:04:05Z"))
+	a.NotError(err).True(v.Equal(sec))
+
+	// 时间戳和没有时区的字符串采用 UTC
+	v, err = Time(sec.Unix())
+	a.NotError(err).Equal(v.Location(), time.UTC)
+	v, err = Time("2024-01-02")
+	a.NotError(err).Equal(v.Location(), time.UTC)
+
+	for _, val := range []any{"", "abc", "2024-13-01", "2024/01/02", "1.2.3", "-"} {
+		_, err = Time(val)
+		a.ErrorIs(err, ErrSyntax, val)
+	}
+
+	for _, val := range []any{uint64(math.MaxUint64), "99999999999999999999", math.Inf(1), math.NaN()} {
+		_, err = Time(val)
+		a.ErrorIs(err, ErrRange, val)
+	}
+
+	_, err = Time(true)
+	a.ErrorIs(err, ErrUnsupported)
+
+	_, err = Time(nil)
+	a.ErrorIs(err, ErrNil)
+
+	a.Equal(MustTime("x", sec), sec).
+		True(MustTime("2024-01-02T03:04:05Z", time.Time{}).Equal(sec))
+}
+
+func TestTime_options(t *testing.T) {
+	a := assert.New(t, false)
+
+	loc := time.FixedZone("UTC+8", 8*3600)
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, loc)
+
+	v, err := Time("2024-01-02 03:04:05", WithLocation(loc))
+	a.NotError(err).True(v.Equal(want)).Equal(v.Location(), loc)
+
+	// 包含时区信息的字符串不受影响
+	v, err = Time("2024-01-02T03:04:05Z", WithLocation(loc))
+	a.NotError(err).True(v.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+	v, err = Time(want.Unix(), WithLocation(loc))
+	a.NotError(err).True(v.Equal(want)).Equal(v.Location(), loc)
+
+	v, err = Time("02/01/2024", WithTimeLayouts("02/01/2006", "2006.01.02"))
+	a.NotError(err).True(v.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+	v, err = Time("2024.01.02", WithTimeLayouts("02/01/2006", "2006.01.02"))
+	a.NotError(err).True(v.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+	_, err = Time("2024-01-02", WithTimeLayouts("02/01/2006"))
+	a.ErrorIs(err, ErrSyntax)
+
+	// 仅包含数字的格式优先于时间戳
+	v, err = Time("20240102", WithTimeLayouts("20060102"))
+	a.NotError(err).True(v.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+	v, err = Time("2024", WithTimeLayouts("2006"))
+	a.NotError(err).True(v.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	v, err = Time("1704164645", WithTimeLayouts("20060102"))
+	a.NotError(err).True(v.Equal(time.Unix(1704164645, 0)))
+	v, err = Time(20240102, WithTimeLayouts("20060102")) // 数值类型依然是时间戳
+	a.NotError(err).True(v.Equal(time.Unix(20240102, 0)))
+
+	// 指定单位
+	v, err = Time(1000, WithTimestampUnit(time.Millisecond))
+	a.NotError(err).True(v.Equal(time.Unix(1, 0)))
+	v, err = Time(int64(1e12), WithTimestampUnit(time.Second))
+	a.NotError(err).True(v.Equal(time.Unix(1e12, 0)))
+	v, err = Time(1000, WithTimestampUnit(time.Hour))
+	a.NotError(err).True(v.Equal(time.Unix(1000, 0)))
+}
+
+func TestTime_reverse(t *testing.T) {
+	a := assert.New(t, false)
+
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+
+	i, err := Int64(tm)
+	a.NotError(err).Equal(i, tm.Unix())
+
+	u, err := Uint64(&tm)
+	a.NotError(err).Equal(u, uint64(tm.Unix()))
+
+	_, err = Uint64(time.Unix(-1, 0))
+	a.ErrorIs(err, ErrNegative)
+
+	s, err := String(tm)
+	a.NotError(err).Equal(s, tm.String())
+
+	b, err := Bytes(tm)
+	a.NotError(err).Equal(b, []byte(tm.String()))
+
+	s, err = String(&tm)
+	a.NotError(err).Equal(s, tm.String())
+
+	b, err = Bytes(&tm)
+	a.NotError(err).Equal(b, []byte(tm.String()))
+
+	_, err = String((*time.Time)(nil))
+	a.ErrorIs(err, ErrNil)
+
+	c := New(WithTimeFormat(time.RFC3339Nano))
+	b, err = c.Bytes(&tm)
+	a.NotError(err).Equal(b, []byte("2024-01-02T03:04:05.0000006Z"))
+
+	c = New(WithTimeFormat("2006-01-02"))
+	s, err = c.String(tm)
+	a.NotError(err).Equal(s, "2024-01-02")
+	s, err = c.String(&tm)
+	a.NotError(err).Equal(s, "2024-01-02")
+
+	// 往返转换
+	v, err := Time(s)
+	a.NotError(err).True(v.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestValue_time(t *testing.T) {
+	a := assert.New(t, false)
+
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var tm time.Time
+	a.NotError(Value("2024-01-02T03:04:05Z", reflect.ValueOf(&tm))).True(tm.Equal(want))
+
+	var p *time.Time
+	a.NotError(Value(want.Unix(), reflect.ValueOf(&p))).True(p.Equal(want))
+
+	a.ErrorIs(Value("x", reflect.ValueOf(&tm)), ErrSyntax)
+
+	type event struct {
+		Created time.Time
+		Updated *time.Time
+		Times   []time.Time
+	}
+	e := &event{}
+	a.NotError(Map2Obj(map[string]any{
+		"Created": "2024-01-02 03:04:05",
+		"Updated": want.UnixMilli(),
+		"Times":   []any{"2024-01-02T03:04:05Z", want},
+	}, e, nil))
+	a.True(e.Created.Equal(want)).
+		True(e.Updated.Equal(want)).
+		Length(e.Times, 2).
+		True(e.Times[0].Equal(want)).
+		True(e.Times[1].Equal(want))
+
+	err := Map2Obj(map[string]any{"Created": "abc"}, e, nil)
+	a.ErrorIs(err, ErrSyntax)
+	var ce *Error
+	a.True(errors.As(err, &ce)).Equal(ce.Path, "Created").Equal(ce.Target, "time.Time")
+
+	// Obj2Map 不再展开 time.Time
+	m, err := Obj2Map(e, nil)
+	a.NotError(err).Equal(m["Created"], e.Created).Equal(m["Updated"], *e.Updated)
+}
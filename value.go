@@ -12,15 +12,39 @@ import (
 
 // Value 将 source 的值保存到成 target 中
 //
-// 如果 source 为 nil，则会将 target 的值设置为其默认的零值。
+// 如果 source 为 nil 或是空指针，则会将 target 的值设置为其默认的零值，
+// source 为指针时，会转换其指向的值。
 //
 // 若类型不能直接转换，会尝试其它种方式转换，比如 [strconv.ParseInt] 等。
-func Value(source any, target reflect.Value) error {
+// 通过 [Register] 注册的转换函数优先于其它所有方式。
+//
+// target 为 [time.Duration] 和 [time.Time] 时，分别由 [Duration] 和 [Time] 进行转换；
+// target 为结构体时，source 可以是 map，转换规则与 [Map2Obj] 相同。
+func Value(source any, target reflect.Value) error { return defaultConverter.Value(source, target) }
+
+// Value 将 source 的值保存到成 target 中
+func (c *Converter) Value(source any, target reflect.Value) error {
 	kind := target.Kind()
 
+	if ok, err := valueRegistered(source, target); ok {
+		return err
+	}
+
 	for kind == reflect.Pointer {
+		if target.IsNil() && target.CanSet() { // 为空指针分配内存
+			if v, ok := indirect(source); ok && v == nil {
+				target.Set(reflect.Zero(target.Type()))
+				return nil
+			}
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+
 		target = target.Elem()
 		kind = target.Kind()
+
+		if ok, err := valueRegistered(source, target); ok {
+			return err
+		}
 	}
 
 	if !target.CanSet() {
@@ -36,33 +60,70 @@ func Value(source any, target reflect.Value) error {
 		return nil
 	}
 
+	if v, ok := indirect(source); ok && kind != reflect.Interface { // 接口可以直接保存指针
+		if v == nil {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		source = v
+	}
+
+	switch target.Type() {
+	case durationType:
+		val, err := c.Duration(source)
+		if err != nil {
+			return err
+		}
+		target.SetInt(int64(val))
+		return nil
+	case timeType:
+		val, err := c.Time(source)
+		if err != nil {
+			return err
+		}
+		target.Set(reflect.ValueOf(val))
+		return nil
+	}
+
 	switch kind {
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		val, err := Uint64(source)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		val, err := c.Uint64(source)
 		if err != nil {
 			return err
 		}
+		if target.OverflowUint(val) {
+			return rangeError(source, target.Type().String())
+		}
 		target.SetUint(val)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		val, err := Int64(source)
+		val, err := c.Int64(source)
 		if err != nil {
 			return err
 		}
+		if target.OverflowInt(val) {
+			return rangeError(source, target.Type().String())
+		}
 		target.SetInt(val)
-	case reflect.Float32, reflect.Float64:
-		val, err := Float64(source)
+	case reflect.Float32:
+		val, err := c.Float32(source)
+		if err != nil {
+			return err
+		}
+		target.SetFloat(float64(val))
+	case reflect.Float64:
+		val, err := c.Float64(source)
 		if err != nil {
 			return err
 		}
 		target.SetFloat(val)
 	case reflect.Bool:
-		val, err := Bool(source)
+		val, err := c.Bool(source)
 		if err != nil {
 			return err
 		}
 		target.SetBool(val)
 	case reflect.String:
-		val, err := String(source)
+		val, err := c.String(source)
 		if err != nil {
 			return err
 		}
@@ -85,8 +146,8 @@ func Value(source any, target reflect.Value) error {
 		tmp := reflect.MakeSlice(target.Type(), l, l)
 		for i := 0; i < l; i++ {
 			si := s.Index(i).Interface()
-			if err := Value(si, tmp.Index(i)); err != nil {
-				return err
+			if err := c.Value(si, tmp.Index(i)); err != nil {
+				return withIndex(err, i)
 			}
 		}
 		target.Set(tmp)
@@ -106,15 +167,53 @@ func Value(source any, target reflect.Value) error {
 
 		l := s.Len()
 		if l != target.Len() {
-			return fmt.Errorf("conv: 两者长度不一样，无法转换 %d: %d", l, target.Len())
+			return newError(source, target.Type().String(), ErrLength, nil)
 		}
 
 		for i := 0; i < l; i++ {
 			si := s.Index(i).Interface()
-			if err := Value(si, target.Index(i)); err != nil {
-				return err
+			if err := c.Value(si, target.Index(i)); err != nil {
+				return withIndex(err, i)
+			}
+		}
+	case reflect.Map:
+		s := reflect.ValueOf(source)
+		if s.Kind() != reflect.Map {
+			return typeError(source, target.Type().String())
+		}
+
+		tt := target.Type()
+		tmp := reflect.MakeMapWithSize(tt, s.Len())
+		iter := s.MapRange()
+		for iter.Next() {
+			sk := iter.Key().Interface()
+
+			k := reflect.New(tt.Key()).Elem()
+			if err := c.Value(sk, k); err != nil {
+				return withPath(err, fmt.Sprint(sk))
 			}
+
+			v := reflect.New(tt.Elem()).Elem()
+			if err := c.Value(iter.Value().Interface(), v); err != nil {
+				return withPath(err, fmt.Sprint(sk))
+			}
+
+			tmp.SetMapIndex(k, v)
+		}
+		target.Set(tmp)
+	case reflect.Struct:
+		s := reflect.ValueOf(source)
+		if s.Kind() != reflect.Map {
+			return valueDefault(source, target)
 		}
+
+		// 先写入临时对象，保证出错时不会修改 target。
+		tmp := reflect.New(target.Type()).Elem()
+		tmp.Set(target)
+		if err := c.map2Struct(s, tmp, nil); err != nil {
+			return err
+		}
+		target.Set(tmp)
 	default:
 		return valueDefault(source, target)
 	}
@@ -122,6 +221,32 @@ func Value(source any, target reflect.Value) error {
 	return nil
 }
 
+// To 将 val 转换成 T 类型
+//
+// 转换规则与 [Value] 相同，T 为指针时会分配内存。
+func To[T any](val any) (T, error) { return ToWith[T](defaultConverter, val) }
+
+// ToWith 采用 c 的转换规则实现 [To]
+func ToWith[T any](c *Converter, val any) (T, error) {
+	var ret T
+	err := c.Value(val, reflect.ValueOf(&ret))
+	return ret, err
+}
+
+// MustTo 将 val 转换成 T 类型或是在无法转换的情况下返回 def 参数
+func MustTo[T any](val any, def ...T) T {
+	ret, err := To[T](val)
+	if err == nil {
+		return ret
+	}
+
+	if len(def) == 0 {
+		panic(err)
+	}
+
+	return def[0]
+}
+
 func valueDefault(source any, target reflect.Value) error {
 	sourceValue := reflect.ValueOf(source)
 	targetType := target.Type()
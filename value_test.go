@@ -107,4 +107,89 @@ func TestValue(t *testing.T) {
 	s20 := "1a23"
 	t20 := 444
 	a.Error(Value(s20, reflect.ValueOf(&t20)))
+
+	// 超出取值范围
+	t21 := int8(1)
+	a.ErrorIs(Value(300, reflect.ValueOf(&t21)), ErrRange)
+	a.Equal(t21, 1)
+	t22 := uint16(1)
+	a.ErrorIs(Value("65536", reflect.ValueOf(&t22)), ErrRange)
+	a.Equal(t22, 1)
+}
+
+func TestTo(t *testing.T) {
+	a := assert.New(t, false)
+
+	v1, err := To[int8]("12")
+	a.NotError(err).Equal(v1, int8(12))
+
+	v2, err := To[string](12)
+	a.NotError(err).Equal(v2, "12")
+
+	v3, err := To[[]int]([]string{"1", "2"})
+	a.NotError(err).Equal(v3, []int{1, 2})
+
+	v4, err := To[[2]uint]([]any{"1", 2})
+	a.NotError(err).Equal(v4, [2]uint{1, 2})
+
+	v5, err := To[*int]("5")
+	a.NotError(err).Equal(*v5, 5)
+
+	v6, err := To[*int](nil)
+	a.NotError(err).Nil(v6)
+
+	v7, err := To[any](5)
+	a.NotError(err).Equal(v7, 5)
+
+	v8, err := To[map[string]uint](map[string]any{"a": "1", "b": 2})
+	a.NotError(err).Equal(v8, map[string]uint{"a": 1, "b": 2})
+
+	_, err = To[int8](300)
+	a.ErrorIs(err, ErrRange)
+
+	v9, err := To[uintptr]("5")
+	a.NotError(err).Equal(v9, uintptr(5))
+
+	_, err = To[uintptr](-1)
+	a.ErrorIs(err, ErrNegative)
+
+	type obj struct{ Ptr uintptr }
+	o := &obj{}
+	a.NotError(Map2Obj(map[string]any{"Ptr": "0x10"}, o, nil)).Equal(o.Ptr, uintptr(16))
+}
+
+func TestMustTo(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(MustTo[int]("5", 6), 5)
+	a.Equal(MustTo[int]("x", 6), 6)
+	a.Equal(MustTo[bool]("on"), true)
+	a.PanicString(func() {
+		MustTo[int]("x")
+	}, "conv: string:x 无法转换成 int64 类型: 格式错误")
+}
+
+func TestValue_struct(t *testing.T) {
+	a := assert.New(t, false)
+
+	t1 := A1{}
+	a.NotError(Value(map[string]any{"ID": 1, "Name": "n"}, reflect.ValueOf(&t1)))
+	a.Equal(t1, A1{ID: 1, Name: "n"})
+
+	t2, err := SliceOf[A1]([]map[string]any{{"ID": 1}, {"Name": "n2"}})
+	a.NotError(err).Equal(t2, []A1{{ID: 1}, {Name: "n2"}})
+
+	t3, err := To[*A1](map[string]any{"ID": 3})
+	a.NotError(err).Equal(t3, &A1{ID: 3})
+
+	t4, err := To[map[string]A1](map[string]any{"a": map[string]any{"ID": 4}})
+	a.NotError(err).Equal(t4, map[string]A1{"a": {ID: 4}})
+
+	// 可直接转换的结构体
+	type a2 A1
+	t5 := A1{}
+	a.NotError(Value(a2{ID: 5}, reflect.ValueOf(&t5)))
+	a.Equal(t5, A1{ID: 5})
+
+	a.ErrorIs(Value(5, reflect.ValueOf(&t5)), ErrUnsupported)
 }